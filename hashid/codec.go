@@ -0,0 +1,61 @@
+package hashid
+
+import (
+	"fmt"
+	"github.com/speps/go-hashids/v2"
+)
+
+const (
+	CodecHashids = "hashids"
+	CodecSqids   = "sqids"
+)
+
+// Codec turns numbers into a hash and back, an Encoder holds one per salt
+type Codec interface {
+	Encode(nums []int64) (string, error)
+	Decode(hash string) ([]int64, error)
+}
+
+// newCodec builds the codec selected by conf.Codec for one salt, a nil conf means the hashids defaults
+func newCodec(conf *Config, salt string) (Codec, error) {
+	c := conf
+	if c == nil {
+		c = &Config{}
+	}
+	switch c.Codec {
+	case "", CodecHashids:
+		h, err := newHash(c, salt)
+		if err != nil {
+			return nil, err
+		}
+		return &hashidsCodec{hash: h}, nil
+	case CodecSqids:
+		return NewSqids(c.Alphabet, c.MinLength, c.Blocklist, salt)
+	default:
+		return nil, fmt.Errorf("hashid codec %q unknown", c.Codec)
+	}
+}
+
+func newHash(conf *Config, salt string) (*hashids.HashID, error) {
+	d := hashids.NewData()
+	d.Salt = salt
+	d.MinLength = conf.MinLength
+	if conf.Alphabet != "" {
+		d.Alphabet = conf.Alphabet
+	}
+	return hashids.NewWithData(d)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type hashidsCodec struct {
+	hash *hashids.HashID
+}
+
+func (h *hashidsCodec) Encode(nums []int64) (string, error) {
+	return h.hash.EncodeInt64(nums)
+}
+
+func (h *hashidsCodec) Decode(hash string) ([]int64, error) {
+	return h.hash.DecodeInt64WithError(hash)
+}
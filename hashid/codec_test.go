@@ -0,0 +1,81 @@
+package hashid
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+// randomNumbers returns 1 to 5 non-negative numbers, mixing small values with the full int64 range
+func randomNumbers(r *rand.Rand) []int64 {
+	nums := make([]int64, 1+r.Intn(5))
+	for i := range nums {
+		switch r.Intn(4) {
+		case 0:
+			nums[i] = int64(r.Intn(100))
+		case 1:
+			nums[i] = math.MaxInt64 - int64(r.Intn(100))
+		default:
+			nums[i] = r.Int63()
+		}
+	}
+	return nums
+}
+
+func testRoundTrip(t *testing.T, c Codec, minLength int, check func(hash string)) {
+	t.Helper()
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		nums := randomNumbers(r)
+		hash, err := c.Encode(nums)
+		if err != nil {
+			t.Fatalf("encode %v: %v", nums, err)
+		}
+		if len(hash) < minLength {
+			t.Fatalf("hash %q of %v shorter than %d", hash, nums, minLength)
+		}
+		got, err := c.Decode(hash)
+		if err != nil {
+			t.Fatalf("decode %q of %v: %v", hash, nums, err)
+		}
+		if !reflect.DeepEqual(got, nums) {
+			t.Fatalf("hash %q decoded to %v, want %v", hash, got, nums)
+		}
+		if check != nil {
+			check(hash)
+		}
+	}
+}
+
+func TestHashidsCodecRoundTrip(t *testing.T) {
+	configs := []*Config{
+		{},
+		{Salt: "boon"},
+		{Salt: "boon", MinLength: 16},
+		{Salt: "boon", Alphabet: "abcdefghijklmnopqrstuvwxyz0123456789"},
+	}
+	for _, conf := range configs {
+		c, err := newCodec(conf, conf.Salt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		testRoundTrip(t, c, conf.MinLength, nil)
+	}
+}
+
+func TestHashidsCodecSaltsDiffer(t *testing.T) {
+	a, err := newCodec(&Config{}, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := newCodec(&Config{}, "b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ha, _ := a.Encode([]int64{42})
+	hb, _ := b.Encode([]int64{42})
+	if ha == hb {
+		t.Fatalf("salts a and b both encode 42 as %q", ha)
+	}
+}
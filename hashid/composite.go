@@ -0,0 +1,233 @@
+package hashid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// CompositeID several numbers, e.g. (tenant id, record id), encoded into one opaque hash. It accepts any number of
+// parts, typed tuples should embed Composite, which fixes it.
+//
+// In sql the numbers are stored comma separated in a string column, columns holding a single part should use
+// Part with a plain integer column instead.
+type CompositeID struct {
+	String  string
+	Numbers []int64
+	alias   string
+}
+
+func NewCompositeID(nums ...int64) (*CompositeID, error) {
+	return SharedEncoder().NewCompositeID(nums...)
+}
+
+// MustNewCompositeID like NewCompositeID, panics on error
+func MustNewCompositeID(nums ...int64) *CompositeID {
+	c, err := NewCompositeID(nums...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Bind makes c encode and decode with the encoder registered under alias
+func (c *CompositeID) Bind(alias string) *CompositeID {
+	c.alias = alias
+	return c
+}
+
+func (c *CompositeID) Alias() string {
+	return c.alias
+}
+
+// Len number of parts
+func (c CompositeID) Len() int {
+	return len(c.Numbers)
+}
+
+// Part returns the i-th number, 0 when c has no such part
+func (c CompositeID) Part(i int) int64 {
+	if i < 0 || i >= len(c.Numbers) {
+		return 0
+	}
+	return c.Numbers[i]
+}
+
+func (c *CompositeID) encoder() (*Encoder, error) {
+	e := GetEncoder(c.alias)
+	if e == nil {
+		return nil, fmt.Errorf("hashid encoder %q not registered", c.alias)
+	}
+	return e, nil
+}
+
+func (c *CompositeID) Scan(src interface{}) error {
+	var text string
+	switch v := src.(type) {
+	case nil:
+		return ErrNullValue
+	case []byte:
+		text = string(v)
+	case string:
+		text = v
+	default:
+		return fmt.Errorf("hashid: unsupported scan source type %T", src)
+	}
+	parts := strings.Split(text, ",")
+	nums := make([]int64, 0, len(parts))
+	for _, part := range parts {
+		num, err := strconv.ParseInt(strings.TrimSpace(part), 10, 64)
+		if err != nil {
+			return err
+		}
+		nums = append(nums, num)
+	}
+	e, err := c.encoder()
+	if err != nil {
+		return err
+	}
+	t, err := e.NewCompositeID(nums...)
+	if err != nil {
+		return err
+	}
+	*c = *t
+	return nil
+}
+
+func (c CompositeID) Value() (driver.Value, error) {
+	parts := make([]string, 0, len(c.Numbers))
+	for _, num := range c.Numbers {
+		parts = append(parts, strconv.FormatInt(num, 10))
+	}
+	return strings.Join(parts, ","), nil
+}
+
+func (c CompositeID) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String)
+}
+
+func (c *CompositeID) UnmarshalJSON(bytes []byte) error {
+	text, null, err := jsonString(bytes)
+	if err != nil || null {
+		return err
+	}
+	return c.UnmarshalText([]byte(text))
+}
+
+func (c CompositeID) MarshalText() ([]byte, error) {
+	return []byte(c.String), nil
+}
+
+func (c *CompositeID) UnmarshalText(text []byte) error {
+	e, err := c.encoder()
+	if err != nil {
+		return err
+	}
+	nums, err := e.DecodeNumbers(string(text))
+	if err != nil {
+		return err
+	}
+	// re-encode, hashes of a legacy salt come back with the current one
+	v, err := e.NewCompositeID(nums...)
+	if err != nil {
+		return err
+	}
+	*c = *v
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for uri, query and form binding
+func (c *CompositeID) UnmarshalParam(param string) error {
+	return c.UnmarshalText([]byte(param))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Shape is implemented by a marker type fixing the number of parts of a Composite
+type Shape interface {
+	Parts() int
+}
+
+// Composite a CompositeID holding exactly the parts of its shape, a hash or sql value with another number of
+// parts fails with ErrInvalidHash, so e.g. a single id can't be decoded as a tuple. Typed tuples embed it and
+// name the parts:
+//
+//	type TenantRecord struct{}
+//	func (TenantRecord) Parts() int { return 2 }
+//
+//	type TenantRecordID struct{ hashid.Composite[TenantRecord] }
+//	func (t TenantRecordID) TenantID() int64 { return t.Part(0) }
+//	func (t TenantRecordID) RecordID() int64 { return t.Part(1) }
+type Composite[S Shape] struct {
+	CompositeID
+}
+
+func NewComposite[S Shape](nums ...int64) (*Composite[S], error) {
+	if n := shapeParts[S](); len(nums) != n {
+		return nil, fmt.Errorf("hashid: composite needs %d parts, got %d", n, len(nums))
+	}
+	c, err := NewCompositeID(nums...)
+	if err != nil {
+		return nil, err
+	}
+	return &Composite[S]{CompositeID: *c}, nil
+}
+
+// MustNewComposite like NewComposite, panics on error
+func MustNewComposite[S Shape](nums ...int64) *Composite[S] {
+	c, err := NewComposite[S](nums...)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func shapeParts[S Shape]() int {
+	var s S
+	return s.Parts()
+}
+
+func (c *Composite[S]) Scan(src interface{}) error {
+	v := CompositeID{alias: c.alias}
+	if err := v.Scan(src); err != nil {
+		return err
+	}
+	if n := shapeParts[S](); v.Len() != n {
+		return fmt.Errorf("%w, want %d parts, got %d", ErrInvalidHash, n, v.Len())
+	}
+	c.CompositeID = v
+	return nil
+}
+
+func (c *Composite[S]) UnmarshalJSON(bytes []byte) error {
+	text, null, err := jsonString(bytes)
+	if err != nil || null {
+		return err
+	}
+	return c.UnmarshalText([]byte(text))
+}
+
+func (c *Composite[S]) UnmarshalText(text []byte) error {
+	e, err := c.encoder()
+	if err != nil {
+		return err
+	}
+	nums, err := e.DecodeN(string(text), shapeParts[S]())
+	if err != nil {
+		return err
+	}
+	// re-encode, hashes of a legacy salt come back with the current one
+	v, err := e.NewCompositeID(nums...)
+	if err != nil {
+		return err
+	}
+	c.CompositeID = *v
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for uri, query and form binding
+func (c *Composite[S]) UnmarshalParam(param string) error {
+	return c.UnmarshalText([]byte(param))
+}
@@ -0,0 +1,79 @@
+package hashid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type tenantRecord struct{}
+
+func (tenantRecord) Parts() int { return 2 }
+
+type tenantRecordID struct {
+	Composite[tenantRecord]
+}
+
+func (t tenantRecordID) TenantID() int64 { return t.Part(0) }
+func (t tenantRecordID) RecordID() int64 { return t.Part(1) }
+
+func TestCompositeRoundTrip(t *testing.T) {
+	id := tenantRecordID{Composite: *MustNewComposite[tenantRecord](3, 42)}
+	bytes, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got tenantRecordID
+	if err := json.Unmarshal(bytes, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got.TenantID() != 3 || got.RecordID() != 42 || got.String != id.String {
+		t.Fatalf("got %+v, want %+v", got, id)
+	}
+
+	value, err := id.Value()
+	if err != nil || value != "3,42" {
+		t.Fatalf("Value got %v, %v", value, err)
+	}
+	var scanned tenantRecordID
+	if err := scanned.Scan(value); err != nil || scanned.String != id.String {
+		t.Fatalf("Scan got %+v, %v", scanned, err)
+	}
+}
+
+func TestCompositeRejectsOtherArity(t *testing.T) {
+	single := MustNewHashID(42)
+	triple := MustNewCompositeID(1, 2, 3)
+	for _, hash := range []string{single.String, triple.String} {
+		var id tenantRecordID
+		if err := json.Unmarshal([]byte(`"`+hash+`"`), &id); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("hash %q decoded as a tuple %+v, err %v", hash, id, err)
+		}
+		if err := id.UnmarshalParam(hash); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("param %q decoded as a tuple %+v, err %v", hash, id, err)
+		}
+	}
+	for _, src := range []interface{}{"42", []byte("1,2,3")} {
+		var id tenantRecordID
+		if err := id.Scan(src); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("Scan %v got %+v, err %v", src, id, err)
+		}
+	}
+	if _, err := NewComposite[tenantRecord](1); err == nil {
+		t.Fatal("NewComposite accepted one part for a pair")
+	}
+}
+
+func TestDecodeN(t *testing.T) {
+	e := SharedEncoder()
+	hash, err := e.EncodeNumbers([]int64{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := e.DecodeN(hash, 2); err != nil || len(d) != 2 {
+		t.Fatalf("got %v, %v", d, err)
+	}
+	if _, err := e.DecodeN(hash, 1); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("got err %v, want ErrInvalidHash", err)
+	}
+}
@@ -0,0 +1,240 @@
+package hashid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+)
+
+var (
+	// LegacySaltCallback is called whenever a hash only decodes with a legacy salt of Config.Salts,
+	// index is the position of that salt, use it to measure salt migration progress.
+	LegacySaltCallback func(alias string, index int)
+)
+
+var (
+	lock       sync.RWMutex
+	encoderMap map[string]*Encoder
+
+	sharedEncoderOnce sync.Once
+	sharedEncoder     *Encoder
+)
+
+func InitWithConfigs(configs []*Config) {
+	for _, conf := range configs {
+		AddEncoder(conf)
+	}
+}
+
+// AddEncoder registers an encoder under conf.Alias, replacing any encoder with the same alias
+func AddEncoder(conf *Config) {
+	e, err := NewEncoder(conf)
+	if err != nil {
+		panic(err)
+	}
+	lock.Lock()
+	defer lock.Unlock()
+	if encoderMap == nil {
+		encoderMap = make(map[string]*Encoder)
+	}
+	encoderMap[conf.Alias] = e
+}
+
+// GetEncoder returns the encoder registered under alias, an empty alias without a registered encoder
+// falls back to the shared encoder configured by InitHashID
+func GetEncoder(alias ...string) *Encoder {
+	k := ""
+	if len(alias) > 0 {
+		k = alias[len(alias)-1]
+	}
+	lock.RLock()
+	e, ok := encoderMap[k]
+	lock.RUnlock()
+	if ok {
+		return e
+	}
+	if k == "" {
+		return SharedEncoder()
+	}
+	return nil
+}
+
+// SharedEncoder the process-wide default encoder, built from the config given to InitHashID.
+// It panics if the config is invalid.
+func SharedEncoder() *Encoder {
+	sharedEncoderOnce.Do(func() {
+		e, err := NewEncoder(sharedConfig())
+		if err != nil {
+			panic(err)
+		}
+		// ids of the shared encoder are not bound to any registered alias
+		e.alias = ""
+		sharedEncoder = e
+	})
+	return sharedEncoder
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Encoder encodes with the codec of the current salt and decodes with the codecs of the current and the legacy salts
+type Encoder struct {
+	alias  string
+	codecs []Codec
+}
+
+func NewEncoder(conf *Config) (*Encoder, error) {
+	e := &Encoder{}
+	var salt string
+	if conf != nil {
+		if err := conf.Validate(); err != nil {
+			return nil, err
+		}
+		e.alias = conf.Alias
+		if salts := conf.salts(); len(salts) > 0 {
+			salt = salts[0]
+		}
+	}
+	c, err := newCodec(conf, salt)
+	if err != nil {
+		return nil, err
+	}
+	e.codecs = append(e.codecs, c)
+	if conf != nil {
+		legacy, err := newLegacyCodecs(conf)
+		if err != nil {
+			return nil, err
+		}
+		e.codecs = append(e.codecs, legacy...)
+	}
+	return e, nil
+}
+
+// NewEncoderWithCodecs builds an encoder from custom codecs, the first one encodes and all of them decode
+func NewEncoderWithCodecs(alias string, codecs ...Codec) (*Encoder, error) {
+	if len(codecs) == 0 {
+		return nil, errors.New("hashid encoder needs at least one codec")
+	}
+	return &Encoder{alias: alias, codecs: codecs}, nil
+}
+
+func newLegacyCodecs(conf *Config) ([]Codec, error) {
+	salts := conf.salts()
+	if len(salts) < 2 {
+		return nil, nil
+	}
+	ans := make([]Codec, 0, len(salts)-1)
+	for _, salt := range salts[1:] {
+		c, err := newCodec(conf, salt)
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, c)
+	}
+	return ans, nil
+}
+
+func (e *Encoder) Encode(num int64) (string, error) {
+	return e.EncodeNumbers([]int64{num})
+}
+
+func (e *Encoder) Decode(hash string) (int64, error) {
+	d, err := e.DecodeNumbers(hash)
+	if err != nil {
+		return 0, err
+	}
+	if len(d) > 1 {
+		return 0, ErrMultiValueHash
+	}
+	return d[0], nil
+}
+
+// EncodeUint64 like Encode, numbers above math.MaxInt64 fail with ErrOverflow
+func (e *Encoder) EncodeUint64(num uint64) (string, error) {
+	if num > math.MaxInt64 {
+		return "", ErrOverflow
+	}
+	return e.Encode(int64(num))
+}
+
+func (e *Encoder) DecodeUint64(hash string) (uint64, error) {
+	num, err := e.Decode(hash)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(num), nil
+}
+
+// EncodeNumbers always encodes with the current salt, negative numbers fail with ErrNegativeNumber
+func (e *Encoder) EncodeNumbers(nums []int64) (string, error) {
+	for _, num := range nums {
+		if num < 0 {
+			return "", ErrNegativeNumber
+		}
+	}
+	return e.codecs[0].Encode(nums)
+}
+
+// DecodeNumbers only accepts canonical hashes, the decoded numbers must encode back to the same string.
+// The current salt is tried first, then the legacy ones in order.
+func (e *Encoder) DecodeNumbers(hash string) ([]int64, error) {
+	if hash == "" {
+		return nil, ErrInvalidHash
+	}
+	var first error
+	for idx, c := range e.codecs {
+		d, err := decodeCanonical(c, hash)
+		if err == nil {
+			if idx > 0 && LegacySaltCallback != nil {
+				LegacySaltCallback(e.alias, idx)
+			}
+			return d, nil
+		}
+		if first == nil {
+			first = err
+		}
+	}
+	return nil, first
+}
+
+// DecodeN like DecodeNumbers, a hash not holding exactly n numbers fails with ErrInvalidHash
+func (e *Encoder) DecodeN(hash string, n int) ([]int64, error) {
+	d, err := e.DecodeNumbers(hash)
+	if err != nil {
+		return nil, err
+	}
+	if len(d) != n {
+		return nil, fmt.Errorf("%w, want %d numbers, got %d", ErrInvalidHash, n, len(d))
+	}
+	return d, nil
+}
+
+func decodeCanonical(c Codec, hash string) ([]int64, error) {
+	d, err := c.Decode(hash)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %s", ErrInvalidHash, err.Error())
+	}
+	if len(d) == 0 {
+		return nil, ErrInvalidHash
+	}
+	if canonical, err := c.Encode(d); err != nil || canonical != hash {
+		return nil, ErrInvalidHash
+	}
+	return d, nil
+}
+
+func (e *Encoder) NewHashID(num int64) (*HashID, error) {
+	ciphertext, err := e.Encode(num)
+	if err != nil {
+		return nil, err
+	}
+	return &HashID{String: ciphertext, Int: num, alias: e.alias}, nil
+}
+
+func (e *Encoder) NewCompositeID(nums ...int64) (*CompositeID, error) {
+	ciphertext, err := e.EncodeNumbers(nums)
+	if err != nil {
+		return nil, err
+	}
+	return &CompositeID{String: ciphertext, Numbers: append([]int64(nil), nums...), alias: e.alias}, nil
+}
@@ -0,0 +1,128 @@
+package hashid
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestEncoderBoundaries(t *testing.T) {
+	e := SharedEncoder()
+	for _, num := range []int64{0, math.MaxInt64} {
+		hash, err := e.Encode(num)
+		if err != nil {
+			t.Fatalf("encode %d: %v", num, err)
+		}
+		got, err := e.Decode(hash)
+		if err != nil || got != num {
+			t.Fatalf("decode %q got %d, %v, want %d", hash, got, err, num)
+		}
+		u, err := e.DecodeUint64(hash)
+		if err != nil || u != uint64(num) {
+			t.Fatalf("decode uint64 %q got %d, %v, want %d", hash, u, err, num)
+		}
+	}
+
+	if _, err := e.Encode(-1); !errors.Is(err, ErrNegativeNumber) {
+		t.Fatalf("encode -1 got err %v", err)
+	}
+	if _, err := e.EncodeNumbers([]int64{1, -1}); !errors.Is(err, ErrNegativeNumber) {
+		t.Fatalf("encode [1 -1] got err %v", err)
+	}
+	if _, err := e.EncodeUint64(math.MaxInt64); err != nil {
+		t.Fatalf("encode uint64 max int64 got err %v", err)
+	}
+	if _, err := e.EncodeUint64(uint64(math.MaxInt64) + 1); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("encode uint64 max int64 + 1 got err %v", err)
+	}
+}
+
+func TestHashIDBoundaries(t *testing.T) {
+	if _, err := NewHashID(-1); !errors.Is(err, ErrNegativeNumber) {
+		t.Fatalf("NewHashID(-1) got err %v", err)
+	}
+	if _, err := NewHashIDUint64(uint64(math.MaxInt64) + 1); !errors.Is(err, ErrOverflow) {
+		t.Fatalf("NewHashIDUint64(max int64 + 1) got err %v", err)
+	}
+	h, err := NewHashIDUint64(math.MaxInt64)
+	if err != nil || h.Uint64() != math.MaxInt64 {
+		t.Fatalf("NewHashIDUint64(max int64) got %+v, %v", h, err)
+	}
+	if h := MustNewHashID(0); h.Int != 0 || h.String == "" {
+		t.Fatalf("MustNewHashID(0) got %+v", h)
+	}
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("MustNewHashID(-1) did not panic")
+		} else if err, ok := r.(error); !ok || !errors.Is(err, ErrNegativeNumber) {
+			t.Fatalf("MustNewHashID(-1) panicked with %v", r)
+		}
+	}()
+	MustNewHashID(-1)
+}
+
+func TestSaltRotation(t *testing.T) {
+	AddEncoder(&Config{Alias: "rotation", Salts: []string{"v3", "v2", "v1"}, MinLength: 8})
+	e := GetEncoder("rotation")
+
+	type call struct {
+		alias string
+		index int
+	}
+	var calls []call
+	LegacySaltCallback = func(alias string, index int) {
+		calls = append(calls, call{alias: alias, index: index})
+	}
+	defer func() {
+		LegacySaltCallback = nil
+	}()
+
+	current, err := e.Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for index, salt := range []string{"v3", "v2", "v1"} {
+		old, err := NewEncoder(&Config{Salt: salt, MinLength: 8})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hash, err := old.Encode(42)
+		if err != nil {
+			t.Fatal(err)
+		}
+		calls = nil
+		if num, err := e.Decode(hash); err != nil || num != 42 {
+			t.Fatalf("salt %s: decoded %q as %d, %v", salt, hash, num, err)
+		}
+		var h HashID
+		if err := h.Bind("rotation").UnmarshalText([]byte(hash)); err != nil {
+			t.Fatalf("salt %s: %v", salt, err)
+		}
+		if h.Int != 42 || h.String != current {
+			t.Fatalf("salt %s: got %+v, want it re-encoded as %q", salt, h, current)
+		}
+		if index == 0 {
+			if len(calls) != 0 {
+				t.Fatalf("current salt reported as legacy: %v", calls)
+			}
+			continue
+		}
+		want := call{alias: "rotation", index: index}
+		if len(calls) != 2 || calls[0] != want || calls[1] != want {
+			t.Fatalf("salt %s: callback calls %v, want two of %v", salt, calls, want)
+		}
+	}
+
+	unknown, err := NewEncoder(&Config{Salt: "v0", MinLength: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := unknown.Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.Decode(hash); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("hash of a retired salt got err %v", err)
+	}
+}
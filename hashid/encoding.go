@@ -0,0 +1,146 @@
+package hashid
+
+import (
+	"fmt"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// text, form binding and bson support, bson stores the number like the sql driver does
+
+func (h HashID) MarshalText() ([]byte, error) {
+	return []byte(h.String), nil
+}
+
+func (h *HashID) UnmarshalText(text []byte) error {
+	e, err := h.encoder()
+	if err != nil {
+		return err
+	}
+	num, err := e.Decode(string(text))
+	if err != nil {
+		return err
+	}
+	// re-encode, hashes of a legacy salt come back with the current one
+	v, err := e.NewHashID(num)
+	if err != nil {
+		return err
+	}
+	*h = *v
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for uri, query and form binding
+func (h *HashID) UnmarshalParam(param string) error {
+	return h.UnmarshalText([]byte(param))
+}
+
+func (h HashID) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(h.Int)
+}
+
+func (h *HashID) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	num, err := bsonInt64(t, data)
+	if err != nil {
+		return err
+	}
+	e, err := h.encoder()
+	if err != nil {
+		return err
+	}
+	v, err := e.NewHashID(num)
+	if err != nil {
+		return err
+	}
+	*h = *v
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (i ID[N]) MarshalText() ([]byte, error) {
+	return []byte(i.String), nil
+}
+
+func (i *ID[N]) UnmarshalText(text []byte) error {
+	c, err := namespaceCodecOf[N]()
+	if err != nil {
+		return err
+	}
+	num, err := c.decode(string(text))
+	if err != nil {
+		return err
+	}
+	// re-encode, hashes of a legacy salt come back with the current one
+	v, err := NewID[N](num)
+	if err != nil {
+		return err
+	}
+	*i = *v
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for uri, query and form binding
+func (i *ID[N]) UnmarshalParam(param string) error {
+	return i.UnmarshalText([]byte(param))
+}
+
+func (i ID[N]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(i.Int)
+}
+
+func (i *ID[N]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	num, err := bsonInt64(t, data)
+	if err != nil {
+		return err
+	}
+	v, err := NewID[N](num)
+	if err != nil {
+		return err
+	}
+	*i = *v
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func (n Named[E]) MarshalText() ([]byte, error) {
+	return []byte(n.String), nil
+}
+
+func (n *Named[E]) UnmarshalText(text []byte) error {
+	h := namedHashID[E]()
+	if err := h.UnmarshalText(text); err != nil {
+		return err
+	}
+	n.String, n.Int = h.String, h.Int
+	return nil
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler for uri, query and form binding
+func (n *Named[E]) UnmarshalParam(param string) error {
+	return n.UnmarshalText([]byte(param))
+}
+
+func (n Named[E]) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(n.Int)
+}
+
+func (n *Named[E]) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	h := namedHashID[E]()
+	if err := h.UnmarshalBSONValue(t, data); err != nil {
+		return err
+	}
+	n.String, n.Int = h.String, h.Int
+	return nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func bsonInt64(t bsontype.Type, data []byte) (int64, error) {
+	num, ok := bson.RawValue{Type: t, Value: data}.AsInt64OK()
+	if !ok {
+		return 0, fmt.Errorf("invalid bson type %s", t)
+	}
+	return num, nil
+}
@@ -4,83 +4,224 @@ import (
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"github.com/speps/go-hashids/v2"
+	"math"
 	"strconv"
 	"sync"
 )
 
+var (
+	// ErrInvalidHash is returned when a string is not a canonical hash of the encoder.
+	ErrInvalidHash = errors.New("hashid: invalid hash")
+
+	// ErrMultiValueHash is returned when a hash holding several numbers is decoded as a single id.
+	ErrMultiValueHash = errors.New("hashid: hash holds multiple values")
+
+	// ErrNullValue is returned when scanning NULL into a HashID, use NullHashID for nullable columns.
+	ErrNullValue = errors.New("hashid: cannot scan NULL value, use NullHashID")
+
+	// ErrNegativeNumber is returned when encoding a negative number.
+	ErrNegativeNumber = errors.New("hashid: negative number not supported")
+
+	// ErrOverflow is returned when an unsigned number doesn't fit in int64.
+	ErrOverflow = errors.New("hashid: number overflows int64")
+
+	// ErrAlreadyInitialized is returned by InitHashID once the shared encoder has been built.
+	ErrAlreadyInitialized = errors.New("hashid: shared encoder already built, InitHashID must be called first")
+)
+
+// Config of an encoder. Salts rotates the salt: the first one encodes and every one of them decodes,
+// Salt is used when Salts is empty. Codec is CodecHashids (default) or CodecSqids, Blocklist only applies to
+// sqids and defaults to DefaultBlocklist.
 type Config struct {
-	MinLength int    `json:"min_length"`
-	Salt      string `json:"salt"`
-	Alphabet  string `json:"alphabet"`
+	Alias     string   `json:"alias"`
+	Codec     string   `json:"codec"`
+	MinLength int      `json:"min_length"`
+	Salt      string   `json:"salt"`
+	Salts     []string `json:"salts"`
+	Alphabet  string   `json:"alphabet"`
+	Blocklist []string `json:"blocklist"`
+}
+
+// Validate checks the codec, the alphabet and the min length
+func (c *Config) Validate() error {
+	if c.MinLength < 0 {
+		return errors.New("hashid config error: min length can't be negative")
+	}
+	var minAlphabet int
+	switch c.Codec {
+	case "", CodecHashids:
+		minAlphabet = 16
+	case CodecSqids:
+		minAlphabet = sqidsMinAlphabet
+		if c.MinLength > sqidsMaxMinLength {
+			return fmt.Errorf("hashid config error: sqids min length can't be greater than %d", sqidsMaxMinLength)
+		}
+	default:
+		return fmt.Errorf("hashid config error: codec %q unknown", c.Codec)
+	}
+	if c.Alphabet == "" {
+		return nil
+	}
+	if n := len([]rune(c.Alphabet)); n < minAlphabet {
+		return fmt.Errorf("hashid config error: alphabet must contain at least %d characters, got %d", minAlphabet, n)
+	}
+	seen := make(map[rune]struct{}, len(c.Alphabet))
+	for _, r := range c.Alphabet {
+		if r == ' ' {
+			return errors.New("hashid config error: alphabet can't contain spaces")
+		}
+		if _, ok := seen[r]; ok {
+			return fmt.Errorf("hashid config error: duplicate character in alphabet: %s", string(r))
+		}
+		seen[r] = struct{}{}
+	}
+	return nil
+}
+
+func (c *Config) salts() []string {
+	if len(c.Salts) > 0 {
+		return c.Salts
+	}
+	if c.Salt != "" {
+		return []string{c.Salt}
+	}
+	return nil
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
 var (
+	initLock       sync.Mutex
+	config         *Config
+	configUsed     bool
 	hashIDOnce     sync.Once
 	hashIDInstance *hashids.HashID
-	config         *Config
 )
 
-func InitHashID(cfg *Config) {
+// InitHashID validates and sets the config of the shared encoder. It must be called before the first id is
+// encoded or decoded, later calls fail with ErrAlreadyInitialized.
+func InitHashID(cfg *Config) error {
+	if cfg != nil {
+		if err := cfg.Validate(); err != nil {
+			return err
+		}
+		c := *cfg
+		cfg = &c
+	}
+	initLock.Lock()
+	defer initLock.Unlock()
+	if configUsed {
+		return ErrAlreadyInitialized
+	}
 	config = cfg
+	return nil
 }
 
+// sharedConfig returns the config given to InitHashID, after which it can no longer change
+func sharedConfig() *Config {
+	initLock.Lock()
+	defer initLock.Unlock()
+	configUsed = true
+	return config
+}
+
+// SharedHashID the hashids instance of the current shared salt, it panics if the config is invalid or the codec
+// is not hashids.
+//
+// Deprecated: it ignores the legacy salts, use SharedEncoder.
 func SharedHashID() *hashids.HashID {
 	hashIDOnce.Do(func() {
-		d := hashids.NewData()
-		if config != nil {
-			if config.MinLength != 0 {
-				d.MinLength = 8
-			}
-			if config.Salt != "" {
-				d.Salt = config.Salt
-			}
-			if config.Alphabet != "" {
-				d.Alphabet = config.Alphabet
-			}
+		conf := sharedConfig()
+		if conf == nil {
+			conf = &Config{}
+		}
+		if conf.Codec != "" && conf.Codec != CodecHashids {
+			panic(fmt.Sprintf("hashid: SharedHashID needs the %s codec, the shared config uses %s, use SharedEncoder",
+				CodecHashids, conf.Codec))
+		}
+		var salt string
+		if salts := conf.salts(); len(salts) > 0 {
+			salt = salts[0]
+		}
+		h, err := newHash(conf, salt)
+		if err != nil {
+			panic(err)
 		}
-		hashIDInstance, _ = hashids.NewWithData(d)
+		hashIDInstance = h
 	})
 	return hashIDInstance
 }
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
+// HashID encoded by the shared encoder, or by the named encoder bound with Bind
 type HashID struct {
 	String string
 	Int    int64
+	alias  string
 }
 
 func NewHashID(num int64) (*HashID, error) {
-	ciphertext, err := SharedHashID().Encode([]int{int(num)})
-	if err != nil {
-		return nil, err
+	return SharedEncoder().NewHashID(num)
+}
+
+// NewHashIDUint64 like NewHashID, numbers above math.MaxInt64 fail with ErrOverflow
+func NewHashIDUint64(num uint64) (*HashID, error) {
+	if num > math.MaxInt64 {
+		return nil, ErrOverflow
 	}
-	return &HashID{String: ciphertext, Int: num}, nil
+	return NewHashID(int64(num))
 }
 
+// MustNewHashID like NewHashID, panics on error
 func MustNewHashID(num int64) *HashID {
-	h, _ := NewHashID(num)
+	h, err := NewHashID(num)
+	if err != nil {
+		panic(err)
+	}
+	return h
+}
+
+// Bind makes h encode and decode with the encoder registered under alias. The alias is stored in the value, so it
+// only affects values built by hand: zero values filled by json, gin binding, GORM or BSON use the shared encoder.
+// Use Named to bind an encoder to a field type.
+func (h *HashID) Bind(alias string) *HashID {
+	h.alias = alias
 	return h
 }
 
+func (h *HashID) Alias() string {
+	return h.alias
+}
+
+// Uint64 the number as uint64, ids are never negative
+func (h *HashID) Uint64() uint64 {
+	return uint64(h.Int)
+}
+
+func (h *HashID) encoder() (*Encoder, error) {
+	e := GetEncoder(h.alias)
+	if e == nil {
+		return nil, fmt.Errorf("hashid encoder %q not registered", h.alias)
+	}
+	return e, nil
+}
+
 func (h *HashID) Scan(src interface{}) error {
-	var num int64
-	switch src.(type) {
-	case []uint8:
-		x, err := strconv.Atoi(string(src.([]uint8)))
-		if err != nil {
-			return err
-		}
-		num = int64(x)
-	case int64:
-		num = src.(int64)
-	default:
-		return errors.New("invalid src type")
+	num, valid, err := scanInt64(src)
+	if err != nil {
+		return err
 	}
-	t, err := NewHashID(num)
+	if !valid {
+		return ErrNullValue
+	}
+	e, err := h.encoder()
+	if err != nil {
+		return err
+	}
+	t, err := e.NewHashID(num)
 	if err != nil {
 		return err
 	}
@@ -97,14 +238,102 @@ func (h *HashID) MarshalJSON() ([]byte, error) {
 }
 
 func (h *HashID) UnmarshalJSON(bytes []byte) error {
-	if bytes[0] == '"' && bytes[len(bytes)-1] == '"' {
-		bytes = bytes[1 : len(bytes)-1]
+	text, null, err := jsonString(bytes)
+	if err != nil || null {
+		return err
+	}
+	return h.UnmarshalText([]byte(text))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// NullHashID a HashID that may be null, like sql.NullString
+type NullHashID struct {
+	HashID HashID
+	Valid  bool
+}
+
+func (n *NullHashID) Scan(src interface{}) error {
+	if src == nil {
+		n.HashID.String, n.HashID.Int, n.Valid = "", 0, false
+		return nil
+	}
+	err := n.HashID.Scan(src)
+	n.Valid = err == nil
+	return err
+}
+
+func (n NullHashID) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
 	}
-	h.String = string(bytes)
-	d, err := SharedHashID().DecodeWithError(h.String)
+	return n.HashID.Value()
+}
+
+func (n NullHashID) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return []byte("null"), nil
+	}
+	return json.Marshal(n.HashID.String)
+}
+
+func (n *NullHashID) UnmarshalJSON(bytes []byte) error {
+	text, null, err := jsonString(bytes)
 	if err != nil {
 		return err
 	}
-	h.Int = int64(d[0])
-	return nil
+	if null {
+		n.HashID.String, n.HashID.Int, n.Valid = "", 0, false
+		return nil
+	}
+	return n.UnmarshalParam(text)
+}
+
+// UnmarshalParam implements gin binding.BindUnmarshaler, an empty param is null
+func (n *NullHashID) UnmarshalParam(param string) error {
+	if param == "" {
+		n.HashID.String, n.HashID.Int, n.Valid = "", 0, false
+		return nil
+	}
+	err := n.HashID.UnmarshalText([]byte(param))
+	n.Valid = err == nil
+	return err
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// scanInt64 reads a sql source, valid is false for NULL
+func scanInt64(src interface{}) (num int64, valid bool, err error) {
+	switch v := src.(type) {
+	case nil:
+		return 0, false, nil
+	case int64:
+		return v, true, nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return 0, false, ErrOverflow
+		}
+		return int64(v), true, nil
+	case []byte:
+		num, err = strconv.ParseInt(string(v), 10, 64)
+	case string:
+		num, err = strconv.ParseInt(v, 10, 64)
+	default:
+		return 0, false, fmt.Errorf("hashid: unsupported scan source type %T", src)
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return num, true, nil
+}
+
+// jsonString decodes a json string, null reports a json null
+func jsonString(bytes []byte) (text string, null bool, err error) {
+	if string(bytes) == "null" {
+		return "", true, nil
+	}
+	if err = json.Unmarshal(bytes, &text); err != nil {
+		return "", false, fmt.Errorf("%w, %s", ErrInvalidHash, err.Error())
+	}
+	return text, false, nil
 }
@@ -0,0 +1,194 @@
+package hashid
+
+import (
+	"errors"
+	"math"
+	"os"
+	"strconv"
+	"testing"
+)
+
+func TestMain(m *testing.M) {
+	if err := InitHashID(&Config{Salt: "boon", MinLength: 8}); err != nil {
+		panic(err)
+	}
+	AddEncoder(&Config{Alias: "order", Salt: "order", MinLength: 8})
+	os.Exit(m.Run())
+}
+
+func TestHashIDScan(t *testing.T) {
+	cases := []struct {
+		name    string
+		src     interface{}
+		want    int64
+		wantErr error
+		anyErr  bool
+	}{
+		{name: "int64", src: int64(42), want: 42},
+		{name: "uint64", src: uint64(42), want: 42},
+		{name: "uint64 max int64", src: uint64(math.MaxInt64), want: math.MaxInt64},
+		{name: "uint64 overflow", src: uint64(math.MaxInt64) + 1, wantErr: ErrOverflow},
+		{name: "bytes", src: []byte("42"), want: 42},
+		{name: "bytes not a number", src: []byte("x"), anyErr: true},
+		{name: "string", src: "42", want: 42},
+		{name: "string not a number", src: "4.2", anyErr: true},
+		{name: "nil", src: nil, wantErr: ErrNullValue},
+		{name: "negative", src: int64(-1), wantErr: ErrNegativeNumber},
+		{name: "unsupported", src: 4.2, anyErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var h HashID
+			err := h.Scan(c.src)
+			switch {
+			case c.wantErr != nil:
+				if !errors.Is(err, c.wantErr) {
+					t.Fatalf("err %v, want %v", err, c.wantErr)
+				}
+			case c.anyErr:
+				if err == nil {
+					t.Fatalf("expected an error, got %+v", h)
+				}
+			default:
+				if err != nil {
+					t.Fatal(err)
+				}
+				if h.Int != c.want || h.String != MustNewHashID(c.want).String {
+					t.Fatalf("got %+v, want %d", h, c.want)
+				}
+			}
+		})
+	}
+}
+
+func TestNullHashIDScan(t *testing.T) {
+	n := NullHashID{HashID: *MustNewHashID(1), Valid: true}
+	if err := n.Scan(nil); err != nil {
+		t.Fatal(err)
+	}
+	if n.Valid || n.HashID.Int != 0 || n.HashID.String != "" {
+		t.Fatalf("nil scanned to %+v", n)
+	}
+	if v, err := n.Value(); v != nil || err != nil {
+		t.Fatalf("Value of null got %v, %v", v, err)
+	}
+	if err := n.Scan(int64(3)); err != nil || !n.Valid || n.HashID.Int != 3 {
+		t.Fatalf("got %+v, %v", n, err)
+	}
+	if err := n.Scan(struct{}{}); err == nil || n.Valid {
+		t.Fatalf("unsupported source got %+v, %v", n, err)
+	}
+}
+
+// decimalCodec encodes numbers as decimal text, "007" decodes to 7 but is not canonical
+type decimalCodec struct{}
+
+func (decimalCodec) Encode(nums []int64) (string, error) {
+	return strconv.FormatInt(nums[0], 10), nil
+}
+
+func (decimalCodec) Decode(hash string) ([]int64, error) {
+	num, err := strconv.ParseInt(hash, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return []int64{num}, nil
+}
+
+func TestDecodeRejectsNonCanonical(t *testing.T) {
+	e, err := NewEncoderWithCodecs("decimal", decimalCodec{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if num, err := e.Decode("7"); err != nil || num != 7 {
+		t.Fatalf("canonical hash got %d, %v", num, err)
+	}
+	if _, err := e.Decode("007"); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("non canonical hash got err %v", err)
+	}
+
+	// the same number encoded without the min length of the shared encoder
+	short, err := NewEncoder(&Config{Salt: "boon"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := short.Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h HashID
+	if err := h.UnmarshalText([]byte(hash)); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("unpadded hash %q got err %v", hash, err)
+	}
+}
+
+func TestHashIDUnmarshalJSON(t *testing.T) {
+	valid := MustNewHashID(9)
+	cases := []struct {
+		name    string
+		input   string
+		want    HashID
+		wantErr bool
+	}{
+		{name: "hash", input: `"` + valid.String + `"`, want: *valid},
+		{name: "null", input: `null`},
+		{name: "empty string", input: `""`, wantErr: true},
+		{name: "empty input", input: ``, wantErr: true},
+		{name: "number", input: `9`, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var h HashID
+			err := h.UnmarshalJSON([]byte(c.input))
+			if c.wantErr {
+				if !errors.Is(err, ErrInvalidHash) {
+					t.Fatalf("err %v, want ErrInvalidHash", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if h.String != c.want.String || h.Int != c.want.Int {
+				t.Fatalf("got %+v, want %+v", h, c.want)
+			}
+		})
+	}
+}
+
+func TestNullHashIDUnmarshalJSON(t *testing.T) {
+	valid := MustNewHashID(9)
+	cases := []struct {
+		name      string
+		input     string
+		wantValid bool
+		wantErr   bool
+	}{
+		{name: "hash", input: `"` + valid.String + `"`, wantValid: true},
+		{name: "null", input: `null`},
+		{name: "empty string", input: `""`},
+		{name: "empty input", input: ``, wantErr: true},
+		{name: "invalid hash", input: `"???"`, wantErr: true},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			n := NullHashID{HashID: *MustNewHashID(1), Valid: true}
+			err := n.UnmarshalJSON([]byte(c.input))
+			if c.wantErr {
+				if !errors.Is(err, ErrInvalidHash) {
+					t.Fatalf("err %v, want ErrInvalidHash", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n.Valid != c.wantValid {
+				t.Fatalf("valid %v, want %v", n.Valid, c.wantValid)
+			}
+			if c.wantValid && n.HashID.Int != 9 {
+				t.Fatalf("got %+v", n)
+			}
+		})
+	}
+}
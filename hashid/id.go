@@ -0,0 +1,171 @@
+package hashid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"reflect"
+	"sync"
+)
+
+// Namespace is implemented by a marker type per model, e.g.
+//
+//	type Order struct{}
+//	func (Order) Salt() string { return "order" }
+//
+// IDs of a namespace are encoded with the shared config and the shared salt joined with the namespace salt
+// ("shared:order"), so the namespace salt needs not be secret. A check number derived from the joined salt is
+// encoded too, so an ID[Order] can't be decoded as an ID[User]; it makes ID hashes a few characters longer than
+// HashID ones. Every shared and namespace legacy salt still decodes.
+type Namespace interface {
+	Salt() string
+}
+
+// LegacyNamespace is optionally implemented by a Namespace whose salt was rotated, its legacy salts still decode
+type LegacyNamespace interface {
+	LegacySalts() []string
+}
+
+var (
+	namespaceLock   sync.Mutex
+	namespaceCodecs = make(map[reflect.Type]*namespaceCodec)
+)
+
+// namespaceCodec checks[0] is the check number of the current salt, the rest belong to legacy salts
+type namespaceCodec struct {
+	encoder *Encoder
+	checks  []int64
+}
+
+func (n *namespaceCodec) encode(num int64) (string, error) {
+	return n.encoder.EncodeNumbers([]int64{num, n.checks[0]})
+}
+
+func (n *namespaceCodec) decode(hash string) (int64, error) {
+	d, err := n.encoder.DecodeNumbers(hash)
+	if err != nil {
+		return 0, err
+	}
+	if len(d) == 2 {
+		for _, check := range n.checks {
+			if d[1] == check {
+				return d[0], nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("%w, hash belongs to another namespace", ErrInvalidHash)
+}
+
+func saltCheck(salt string) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(salt))
+	return int64(h.Sum32() & 0xffff)
+}
+
+// namespaceSalts joins every shared salt with every namespace salt, the current ones first
+func namespaceSalts(shared []string, n Namespace) []string {
+	salts := []string{n.Salt()}
+	if legacy, ok := n.(LegacyNamespace); ok {
+		salts = append(salts, legacy.LegacySalts()...)
+	}
+	if len(shared) == 0 {
+		return salts
+	}
+	ans := make([]string, 0, len(shared)*len(salts))
+	for _, s := range shared {
+		for _, salt := range salts {
+			ans = append(ans, s+":"+salt)
+		}
+	}
+	return ans
+}
+
+func namespaceCodecOf[N Namespace]() (*namespaceCodec, error) {
+	var n N
+	typ := reflect.TypeOf(&n).Elem()
+	namespaceLock.Lock()
+	defer namespaceLock.Unlock()
+	if e, ok := namespaceCodecs[typ]; ok {
+		return e, nil
+	}
+	conf := Config{}
+	if shared := sharedConfig(); shared != nil {
+		conf = *shared
+	}
+	// the alias is only reported to LegacySaltCallback, the encoder is not registered
+	conf.Alias = typ.String()
+	conf.Salts = namespaceSalts(conf.salts(), n)
+	conf.Salt = ""
+	e, err := NewEncoder(&conf)
+	if err != nil {
+		return nil, err
+	}
+	c := &namespaceCodec{encoder: e, checks: make([]int64, 0, len(conf.Salts))}
+	for _, salt := range conf.Salts {
+		c.checks = append(c.checks, saltCheck(salt))
+	}
+	namespaceCodecs[typ] = c
+	return c, nil
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// ID a HashID typed by its namespace
+type ID[N Namespace] struct {
+	String string
+	Int    int64
+}
+
+func NewID[N Namespace](num int64) (*ID[N], error) {
+	e, err := namespaceCodecOf[N]()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := e.encode(num)
+	if err != nil {
+		return nil, err
+	}
+	return &ID[N]{String: ciphertext, Int: num}, nil
+}
+
+// MustNewID like NewID, panics on error
+func MustNewID[N Namespace](num int64) *ID[N] {
+	id, err := NewID[N](num)
+	if err != nil {
+		panic(err)
+	}
+	return id
+}
+
+func (i *ID[N]) Scan(src interface{}) error {
+	num, valid, err := scanInt64(src)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return ErrNullValue
+	}
+	t, err := NewID[N](num)
+	if err != nil {
+		return err
+	}
+	*i = *t
+	return nil
+}
+
+func (i ID[N]) Value() (driver.Value, error) {
+	return i.Int, nil
+}
+
+func (i *ID[N]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(i.String)
+}
+
+func (i *ID[N]) UnmarshalJSON(bytes []byte) error {
+	text, null, err := jsonString(bytes)
+	if err != nil || null {
+		return err
+	}
+	return i.UnmarshalText([]byte(text))
+}
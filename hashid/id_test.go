@@ -0,0 +1,94 @@
+package hashid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type userNamespace struct{}
+
+func (userNamespace) Salt() string { return "user" }
+
+type orderNamespace struct{}
+
+func (orderNamespace) Salt() string { return "order" }
+
+type rotatedNamespace struct{}
+
+func (rotatedNamespace) Salt() string          { return "rotated-v2" }
+func (rotatedNamespace) LegacySalts() []string { return []string{"rotated-v1"} }
+
+func TestIDRoundTrip(t *testing.T) {
+	id := MustNewID[userNamespace](42)
+	bytes, err := json.Marshal(id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got ID[userNamespace]
+	if err := json.Unmarshal(bytes, &got); err != nil {
+		t.Fatal(err)
+	}
+	if got != *id {
+		t.Fatalf("got %+v, want %+v", got, *id)
+	}
+	var scanned ID[userNamespace]
+	if err := scanned.Scan(int64(42)); err != nil || scanned != *id {
+		t.Fatalf("Scan got %+v, %v", scanned, err)
+	}
+}
+
+func TestIDNamespacesDontCrossDecode(t *testing.T) {
+	for num := int64(0); num < 2000; num++ {
+		user := MustNewID[userNamespace](num)
+		var order ID[orderNamespace]
+		if err := order.UnmarshalText([]byte(user.String)); !errors.Is(err, ErrInvalidHash) {
+			t.Fatalf("ID[user] %q of %d decoded as ID[order] %+v, err %v", user.String, num, order, err)
+		}
+	}
+}
+
+func TestIDUsesSharedSalt(t *testing.T) {
+	id := MustNewID[orderNamespace](42)
+	// an encoder knowing only the public namespace salt must not reproduce nor decode the hash
+	public, err := NewEncoder(&Config{Salt: "order", MinLength: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := public.DecodeNumbers(id.String); err == nil {
+		t.Fatalf("hash %q decoded without the shared salt", id.String)
+	}
+	joined, err := NewEncoder(&Config{Salt: "boon:order", MinLength: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if d, err := joined.DecodeNumbers(id.String); err != nil || d[0] != 42 {
+		t.Fatalf("hash %q decoded with the joined salt as %v, %v", id.String, d, err)
+	}
+}
+
+func TestIDLegacyNamespaceSalt(t *testing.T) {
+	legacy, err := NewEncoder(&Config{Salt: "boon:rotated-v1", MinLength: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	c, err := namespaceCodecOf[rotatedNamespace]()
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := legacy.EncodeNumbers([]int64{42, saltCheck("boon:rotated-v1")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var id ID[rotatedNamespace]
+	if err := id.UnmarshalText([]byte(hash)); err != nil {
+		t.Fatal(err)
+	}
+	current, err := c.encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if id.Int != 42 || id.String != current {
+		t.Fatalf("got %+v, want 42 re-encoded as %q", id, current)
+	}
+}
@@ -0,0 +1,83 @@
+package hashid
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// EncoderAlias is implemented by a marker type naming an encoder registered with AddEncoder or InitWithConfigs, e.g.
+//
+//	type OrderEncoder struct{}
+//	func (OrderEncoder) Alias() string { return "order" }
+type EncoderAlias interface {
+	Alias() string
+}
+
+// Named a HashID encoded by the encoder its type names. Unlike HashID.Bind, the binding also holds for zero values
+// filled by json, gin binding, GORM or BSON, so models can use a different encoder per entity.
+type Named[E EncoderAlias] struct {
+	String string
+	Int    int64
+}
+
+func NewNamed[E EncoderAlias](num int64) (*Named[E], error) {
+	h, err := namedHashID[E]().encodeFrom(num)
+	if err != nil {
+		return nil, err
+	}
+	return &Named[E]{String: h.String, Int: h.Int}, nil
+}
+
+// MustNewNamed like NewNamed, panics on error
+func MustNewNamed[E EncoderAlias](num int64) *Named[E] {
+	n, err := NewNamed[E](num)
+	if err != nil {
+		panic(err)
+	}
+	return n
+}
+
+func (n *Named[E]) Alias() string {
+	var e E
+	return e.Alias()
+}
+
+func (n *Named[E]) Scan(src interface{}) error {
+	h := namedHashID[E]()
+	if err := h.Scan(src); err != nil {
+		return err
+	}
+	n.String, n.Int = h.String, h.Int
+	return nil
+}
+
+func (n Named[E]) Value() (driver.Value, error) {
+	return n.Int, nil
+}
+
+func (n *Named[E]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.String)
+}
+
+func (n *Named[E]) UnmarshalJSON(bytes []byte) error {
+	text, null, err := jsonString(bytes)
+	if err != nil || null {
+		return err
+	}
+	return n.UnmarshalText([]byte(text))
+}
+
+// namedHashID returns an empty HashID bound to the alias of E
+func namedHashID[E EncoderAlias]() *HashID {
+	var e E
+	return (&HashID{}).Bind(e.Alias())
+}
+
+// encodeFrom encodes num with the encoder h is bound to
+func (h *HashID) encodeFrom(num int64) (*HashID, error) {
+	e, err := h.encoder()
+	if err != nil {
+		return nil, err
+	}
+	return e.NewHashID(num)
+}
@@ -0,0 +1,59 @@
+package hashid
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+type orderEncoder struct{}
+
+func (orderEncoder) Alias() string { return "order" }
+
+type missingEncoder struct{}
+
+func (missingEncoder) Alias() string { return "missing" }
+
+func TestNamedUsesTypeEncoder(t *testing.T) {
+	want, err := GetEncoder("order").Encode(42)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := MustNewNamed[orderEncoder](42)
+	if n.String != want {
+		t.Fatalf("NewNamed encoded %q, want %q", n.String, want)
+	}
+	if shared := MustNewHashID(42); shared.String == want {
+		t.Fatalf("order and shared encoders produced the same hash %q", want)
+	}
+}
+
+func TestNamedZeroValueDecoding(t *testing.T) {
+	hash, err := GetEncoder("order").Encode(7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var model struct {
+		ID Named[orderEncoder] `json:"id"`
+	}
+	if err := json.Unmarshal([]byte(`{"id":"`+hash+`"}`), &model); err != nil {
+		t.Fatal(err)
+	}
+	if model.ID.Int != 7 || model.ID.String != hash {
+		t.Fatalf("got %+v", model.ID)
+	}
+
+	var n Named[orderEncoder]
+	if err := n.Scan(int64(7)); err != nil || n.String != hash {
+		t.Fatalf("Scan got %+v, %v", n, err)
+	}
+	if err := n.UnmarshalParam(MustNewHashID(7).String); !errors.Is(err, ErrInvalidHash) {
+		t.Fatalf("shared hash decoded by the order encoder, err %v", err)
+	}
+}
+
+func TestNamedUnregistered(t *testing.T) {
+	if _, err := NewNamed[missingEncoder](1); err == nil {
+		t.Fatal("expected an error for an unregistered alias")
+	}
+}
@@ -0,0 +1,312 @@
+package hashid
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	sqidsDefaultAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
+	sqidsMinAlphabet     = 3
+	sqidsMaxMinLength    = 255
+)
+
+// DefaultBlocklist words Sqids never produces when Config.Blocklist is nil. It is the default blocklist of the
+// official Sqids implementations (sqids-go v0.4.1, MIT licensed), so default hashes match theirs.
+var DefaultBlocklist = []string{
+	"0rgasm", "1d10t", "1d1ot", "1di0t", "1diot", "1eccacu10", "1eccacu1o", "1eccacul0", "1eccaculo", "1mbec11e",
+	"1mbec1le", "1mbeci1e", "1mbecile", "a11upat0", "a11upato", "a1lupat0", "a1lupato", "aand", "ah01e", "ah0le",
+	"aho1e", "ahole", "al1upat0", "al1upato", "allupat0", "allupato", "ana1", "ana1e", "anal", "anale", "anus",
+	"arrapat0", "arrapato", "arsch", "arse", "ass", "b00b", "b00be", "b01ata", "b0ceta", "b0iata", "b0ob", "b0obe",
+	"b0sta", "b1tch", "b1te", "b1tte", "ba1atkar", "balatkar", "bastard0", "bastardo", "batt0na", "battona", "bitch",
+	"bite", "bitte", "bo0b", "bo0be", "bo1ata", "boceta", "boiata", "boob", "boobe", "bosta", "bran1age", "bran1er",
+	"bran1ette", "bran1eur", "bran1euse", "branlage", "branler", "branlette", "branleur", "branleuse", "c0ck",
+	"c0g110ne", "c0g11one", "c0g1i0ne", "c0g1ione", "c0gl10ne", "c0gl1one", "c0gli0ne", "c0glione", "c0na", "c0nnard",
+	"c0nnasse", "c0nne", "c0u111es", "c0u11les", "c0u1l1es", "c0u1lles", "c0ui11es", "c0ui1les", "c0uil1es",
+	"c0uilles", "c11t", "c11t0", "c11to", "c1it", "c1it0", "c1ito", "cabr0n", "cabra0", "cabrao", "cabron", "caca",
+	"cacca", "cacete", "cagante", "cagar", "cagare", "cagna", "cara1h0", "cara1ho", "caracu10", "caracu1o", "caracul0",
+	"caraculo", "caralh0", "caralho", "cazz0", "cazz1mma", "cazzata", "cazzimma", "cazzo", "ch00t1a", "ch00t1ya",
+	"ch00tia", "ch00tiya", "ch0d", "ch0ot1a", "ch0ot1ya", "ch0otia", "ch0otiya", "ch1asse", "ch1avata", "ch1er",
+	"ch1ng0", "ch1ngadaz0s", "ch1ngadazos", "ch1ngader1ta", "ch1ngaderita", "ch1ngar", "ch1ngo", "ch1ngues", "ch1nk",
+	"chatte", "chiasse", "chiavata", "chier", "ching0", "chingadaz0s", "chingadazos", "chingader1ta", "chingaderita",
+	"chingar", "chingo", "chingues", "chink", "cho0t1a", "cho0t1ya", "cho0tia", "cho0tiya", "chod", "choot1a",
+	"choot1ya", "chootia", "chootiya", "cl1t", "cl1t0", "cl1to", "clit", "clit0", "clito", "cock", "cog110ne",
+	"cog11one", "cog1i0ne", "cog1ione", "cogl10ne", "cogl1one", "cogli0ne", "coglione", "cona", "connard", "connasse",
+	"conne", "cou111es", "cou11les", "cou1l1es", "cou1lles", "coui11es", "coui1les", "couil1es", "couilles", "cracker",
+	"crap", "cu10", "cu1att0ne", "cu1attone", "cu1er0", "cu1ero", "cu1o", "cul0", "culatt0ne", "culattone", "culer0",
+	"culero", "culo", "cum", "cunt", "d11d0", "d11do", "d1ck", "d1ld0", "d1ldo", "damn", "de1ch", "deich", "depp",
+	"di1d0", "di1do", "dick", "dild0", "dildo", "dyke", "encu1e", "encule", "enema", "enf01re", "enf0ire", "enfo1re",
+	"enfoire", "estup1d0", "estup1do", "estupid0", "estupido", "etr0n", "etron", "f0da", "f0der", "f0ttere",
+	"f0tters1", "f0ttersi", "f0tze", "f0utre", "f1ca", "f1cker", "f1ga", "fag", "fica", "ficker", "figa", "foda",
+	"foder", "fottere", "fotters1", "fottersi", "fotze", "foutre", "fr0c10", "fr0c1o", "fr0ci0", "fr0cio", "fr0sc10",
+	"fr0sc1o", "fr0sci0", "fr0scio", "froc10", "froc1o", "froci0", "frocio", "frosc10", "frosc1o", "frosci0",
+	"froscio", "fuck", "g00", "g0o", "g0u1ne", "g0uine", "gandu", "go0", "goo", "gou1ne", "gouine", "gr0gnasse",
+	"grognasse", "haram1", "harami", "haramzade", "hund1n", "hundin", "id10t", "id1ot", "idi0t", "idiot", "imbec11e",
+	"imbec1le", "imbeci1e", "imbecile", "j1zz", "jerk", "jizz", "k1ke", "kam1ne", "kamine", "kike", "leccacu10",
+	"leccacu1o", "leccacul0", "leccaculo", "m1erda", "m1gn0tta", "m1gnotta", "m1nch1a", "m1nchia", "m1st", "mam0n",
+	"mamahuev0", "mamahuevo", "mamon", "masturbat10n", "masturbat1on", "masturbate", "masturbati0n", "masturbation",
+	"merd0s0", "merd0so", "merda", "merde", "merdos0", "merdoso", "mierda", "mign0tta", "mignotta", "minch1a",
+	"minchia", "mist", "musch1", "muschi", "n1gger", "neger", "negr0", "negre", "negro", "nerch1a", "nerchia",
+	"nigger", "orgasm", "p00p", "p011a", "p01la", "p0l1a", "p0lla", "p0mp1n0", "p0mp1no", "p0mpin0", "p0mpino", "p0op",
+	"p0rca", "p0rn", "p0rra", "p0uff1asse", "p0uffiasse", "p1p1", "p1pi", "p1r1a", "p1rla", "p1sc10", "p1sc1o",
+	"p1sci0", "p1scio", "p1sser", "pa11e", "pa1le", "pal1e", "palle", "pane1e1r0", "pane1e1ro", "pane1eir0",
+	"pane1eiro", "panele1r0", "panele1ro", "paneleir0", "paneleiro", "patakha", "pec0r1na", "pec0rina", "pecor1na",
+	"pecorina", "pen1s", "pendej0", "pendejo", "penis", "pip1", "pipi", "pir1a", "pirla", "pisc10", "pisc1o", "pisci0",
+	"piscio", "pisser", "po0p", "po11a", "po1la", "pol1a", "polla", "pomp1n0", "pomp1no", "pompin0", "pompino", "poop",
+	"porca", "porn", "porra", "pouff1asse", "pouffiasse", "pr1ck", "prick", "pussy", "put1za", "puta", "puta1n",
+	"putain", "pute", "putiza", "puttana", "queca", "r0mp1ba11e", "r0mp1ba1le", "r0mp1bal1e", "r0mp1balle",
+	"r0mpiba11e", "r0mpiba1le", "r0mpibal1e", "r0mpiballe", "rand1", "randi", "rape", "recch10ne", "recch1one",
+	"recchi0ne", "recchione", "retard", "romp1ba11e", "romp1ba1le", "romp1bal1e", "romp1balle", "rompiba11e",
+	"rompiba1le", "rompibal1e", "rompiballe", "ruff1an0", "ruff1ano", "ruffian0", "ruffiano", "s1ut", "sa10pe",
+	"sa1aud", "sa1ope", "sacanagem", "sal0pe", "salaud", "salope", "saugnapf", "sb0rr0ne", "sb0rra", "sb0rrone",
+	"sbattere", "sbatters1", "sbattersi", "sborr0ne", "sborra", "sborrone", "sc0pare", "sc0pata", "sch1ampe",
+	"sche1se", "sche1sse", "scheise", "scheisse", "schlampe", "schwachs1nn1g", "schwachs1nnig", "schwachsinn1g",
+	"schwachsinnig", "schwanz", "scopare", "scopata", "sexy", "sh1t", "shit", "slut", "sp0mp1nare", "sp0mpinare",
+	"spomp1nare", "spompinare", "str0nz0", "str0nza", "str0nzo", "stronz0", "stronza", "stronzo", "stup1d", "stupid",
+	"succh1am1", "succh1ami", "succhiam1", "succhiami", "sucker", "t0pa", "tapette", "test1c1e", "test1cle",
+	"testic1e", "testicle", "tette", "topa", "tr01a", "tr0ia", "tr0mbare", "tr1ng1er", "tr1ngler", "tring1er",
+	"tringler", "tro1a", "troia", "trombare", "turd", "twat", "vaffancu10", "vaffancu1o", "vaffancul0", "vaffanculo",
+	"vag1na", "vagina", "verdammt", "verga", "w1chsen", "wank", "wichsen", "x0ch0ta", "x0chota", "xana", "xoch0ta",
+	"xochota", "z0cc01a", "z0cc0la", "z0cco1a", "z0ccola", "z1z1", "z1zi", "ziz1", "zizi", "zocc01a", "zocc0la",
+	"zocco1a", "zoccola",
+}
+
+// Sqids codec following the sqids.org algorithm. Sqids has no salt of its own, a non-empty salt shuffles the
+// alphabet first, so salt rotation works the same way as with hashids.
+type Sqids struct {
+	alphabet  []byte
+	minLength int
+	blocklist []string
+}
+
+func NewSqids(alphabet string, minLength int, blocklist []string, salt string) (*Sqids, error) {
+	if alphabet == "" {
+		alphabet = sqidsDefaultAlphabet
+	}
+	if blocklist == nil {
+		blocklist = DefaultBlocklist
+	}
+	if len(alphabet) < sqidsMinAlphabet {
+		return nil, fmt.Errorf("sqids alphabet must contain at least %d characters", sqidsMinAlphabet)
+	}
+	seen := make(map[rune]struct{}, len(alphabet))
+	for _, r := range alphabet {
+		if r >= 0x80 {
+			return nil, errors.New("sqids alphabet can only contain ascii characters")
+		}
+		if _, ok := seen[r]; ok {
+			return nil, fmt.Errorf("duplicate character in sqids alphabet: %s", string(r))
+		}
+		seen[r] = struct{}{}
+	}
+	if minLength < 0 || minLength > sqidsMaxMinLength {
+		return nil, fmt.Errorf("sqids min length must be between 0 and %d", sqidsMaxMinLength)
+	}
+
+	// keep blocklist words of at least 3 characters made of alphabet characters only
+	lower := strings.ToLower(alphabet)
+	words := make([]string, 0, len(blocklist))
+	for _, word := range blocklist {
+		word = strings.ToLower(word)
+		if len(word) < 3 {
+			continue
+		}
+		valid := true
+		for _, r := range word {
+			if !strings.ContainsRune(lower, r) {
+				valid = false
+				break
+			}
+		}
+		if valid {
+			words = append(words, word)
+		}
+	}
+
+	chars := []byte(alphabet)
+	if salt != "" {
+		saltShuffle(chars, []byte(salt))
+	}
+	sqidsShuffle(chars)
+	return &Sqids{alphabet: chars, minLength: minLength, blocklist: words}, nil
+}
+
+func (s *Sqids) Encode(nums []int64) (string, error) {
+	if len(nums) == 0 {
+		return "", errors.New("encoding empty array of numbers makes no sense")
+	}
+	for _, num := range nums {
+		if num < 0 {
+			return "", errors.New("negative number not supported")
+		}
+	}
+	return s.encode(nums, 0)
+}
+
+func (s *Sqids) encode(nums []int64, increment int) (string, error) {
+	size := len(s.alphabet)
+	if increment > size {
+		return "", errors.New("reached max attempts to re-generate the sqids id")
+	}
+
+	offset := len(nums)
+	for i, num := range nums {
+		offset += int(s.alphabet[num%int64(size)]) + i
+	}
+	offset = (offset%size + increment) % size
+
+	alphabet := make([]byte, 0, size)
+	alphabet = append(alphabet, s.alphabet[offset:]...)
+	alphabet = append(alphabet, s.alphabet[:offset]...)
+	prefix := alphabet[0]
+	reverse(alphabet)
+
+	id := []byte{prefix}
+	for i, num := range nums {
+		id = append(id, sqidsToID(num, alphabet[1:])...)
+		if i < len(nums)-1 {
+			id = append(id, alphabet[0])
+			sqidsShuffle(alphabet)
+		}
+	}
+
+	if s.minLength > len(id) {
+		id = append(id, alphabet[0])
+		for s.minLength-len(id) > 0 {
+			sqidsShuffle(alphabet)
+			n := s.minLength - len(id)
+			if n > size {
+				n = size
+			}
+			id = append(id, alphabet[:n]...)
+		}
+	}
+
+	if s.blocked(string(id)) {
+		return s.encode(nums, increment+1)
+	}
+	return string(id), nil
+}
+
+func (s *Sqids) Decode(hash string) ([]int64, error) {
+	ans := make([]int64, 0)
+	if hash == "" {
+		return ans, nil
+	}
+	for i := 0; i < len(hash); i++ {
+		if indexByte(s.alphabet, hash[i]) < 0 {
+			return nil, errors.New("sqids id contains characters outside the alphabet")
+		}
+	}
+
+	offset := indexByte(s.alphabet, hash[0])
+	size := len(s.alphabet)
+	alphabet := make([]byte, 0, size)
+	alphabet = append(alphabet, s.alphabet[offset:]...)
+	alphabet = append(alphabet, s.alphabet[:offset]...)
+	reverse(alphabet)
+
+	id := hash[1:]
+	for len(id) > 0 {
+		separator := string(alphabet[0])
+		chunks := strings.Split(id, separator)
+		if chunks[0] == "" {
+			return ans, nil
+		}
+		num, err := sqidsToNumber(chunks[0], alphabet[1:])
+		if err != nil {
+			return nil, err
+		}
+		ans = append(ans, num)
+		if len(chunks) > 1 {
+			sqidsShuffle(alphabet)
+		}
+		id = strings.Join(chunks[1:], separator)
+	}
+	return ans, nil
+}
+
+func (s *Sqids) blocked(id string) bool {
+	id = strings.ToLower(id)
+	for _, word := range s.blocklist {
+		if len(word) > len(id) {
+			continue
+		}
+		if len(id) <= 3 || len(word) <= 3 {
+			if id == word {
+				return true
+			}
+		} else if strings.ContainsAny(word, "0123456789") {
+			if strings.HasPrefix(id, word) || strings.HasSuffix(id, word) {
+				return true
+			}
+		} else if strings.Contains(id, word) {
+			return true
+		}
+	}
+	return false
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+func sqidsShuffle(chars []byte) {
+	size := len(chars)
+	for i, j := 0, size-1; j > 0; i, j = i+1, j-1 {
+		r := (i*j + int(chars[i]) + int(chars[j])) % size
+		chars[i], chars[r] = chars[r], chars[i]
+	}
+}
+
+// saltShuffle the consistent shuffle of hashids
+func saltShuffle(chars []byte, salt []byte) {
+	for i, v, p := len(chars)-1, 0, 0; i > 0; i-- {
+		p += int(salt[v])
+		j := (int(salt[v]) + v + p) % i
+		chars[i], chars[j] = chars[j], chars[i]
+		v = (v + 1) % len(salt)
+	}
+}
+
+func sqidsToID(num int64, alphabet []byte) []byte {
+	size := int64(len(alphabet))
+	id := make([]byte, 0, 8)
+	for {
+		id = append(id, alphabet[num%size])
+		num /= size
+		if num == 0 {
+			break
+		}
+	}
+	reverse(id)
+	return id
+}
+
+func sqidsToNumber(id string, alphabet []byte) (int64, error) {
+	size := int64(len(alphabet))
+	var num int64
+	for i := 0; i < len(id); i++ {
+		idx := int64(indexByte(alphabet, id[i]))
+		if num > (math.MaxInt64-idx)/size {
+			return 0, errors.New("sqids id overflows int64")
+		}
+		num = num*size + idx
+	}
+	return num, nil
+}
+
+func reverse(chars []byte) {
+	for i, j := 0, len(chars)-1; i < j; i, j = i+1, j-1 {
+		chars[i], chars[j] = chars[j], chars[i]
+	}
+}
+
+func indexByte(chars []byte, c byte) int {
+	for i, v := range chars {
+		if v == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,101 @@
+package hashid
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSqidsVectors(t *testing.T) {
+	cases := []struct {
+		name      string
+		minLength int
+		blocklist []string
+		nums      []int64
+		want      string
+	}{
+		{name: "default", nums: []int64{1, 2, 3}, want: "86Rf07"},
+		{name: "min length", minLength: 10, nums: []int64{1, 2, 3}, want: "86Rf07xd4z"},
+		{name: "blocklist", blocklist: []string{"86Rf07"}, nums: []int64{1, 2, 3}, want: "se8ojk"},
+		{name: "default blocklist", nums: []int64{4572721}, want: "JExTR"},
+		{name: "empty blocklist", blocklist: []string{}, nums: []int64{4572721}, want: "aho1e"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSqids("", c.minLength, c.blocklist, "")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := s.Encode(c.nums)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Fatalf("encoded %v as %q, want %q", c.nums, got, c.want)
+			}
+			nums, err := s.Decode(got)
+			if err != nil || !reflect.DeepEqual(nums, c.nums) {
+				t.Fatalf("decoded %q as %v, %v", got, nums, err)
+			}
+		})
+	}
+}
+
+func TestSqidsDefaultBlocklistDecodes(t *testing.T) {
+	s, err := NewSqids("", 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a blocked id is never produced, but still decodes like in the other implementations
+	nums, err := s.Decode("aho1e")
+	if err != nil || !reflect.DeepEqual(nums, []int64{4572721}) {
+		t.Fatalf("got %v, %v", nums, err)
+	}
+	if len(s.blocklist) < 500 {
+		t.Fatalf("default blocklist holds %d words", len(s.blocklist))
+	}
+}
+
+func TestSqidsRoundTrip(t *testing.T) {
+	cases := []struct {
+		name      string
+		salt      string
+		minLength int
+		blocklist []string
+	}{
+		{name: "default"},
+		{name: "salt", salt: "boon"},
+		{name: "min length", minLength: 32},
+		{name: "salt and min length", salt: "boon", minLength: 12},
+		{name: "custom blocklist", blocklist: []string{"abc", "xyz", "q0q"}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s, err := NewSqids("", c.minLength, c.blocklist, c.salt)
+			if err != nil {
+				t.Fatal(err)
+			}
+			words := s.blocklist
+			testRoundTrip(t, s, c.minLength, func(hash string) {
+				if s.blocked(hash) {
+					t.Fatalf("hash %q contains a blocked word of %v", hash, words)
+				}
+			})
+		})
+	}
+}
+
+func TestSqidsSaltChangesAlphabet(t *testing.T) {
+	plain, err := NewSqids("", 0, nil, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	salted, err := NewSqids("", 0, nil, "boon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := plain.Encode([]int64{1, 2, 3})
+	b, _ := salted.Encode([]int64{1, 2, 3})
+	if a == b {
+		t.Fatalf("salt did not change the hash %q", a)
+	}
+}
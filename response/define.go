@@ -0,0 +1,68 @@
+package response
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+var (
+	definitionLock sync.RWMutex
+	definitionMap  map[int]Handler
+)
+
+// Definition a business error declared with Define
+type Definition struct {
+	Code       int
+	StatusCode int
+	Key        string
+}
+
+// Define declares a business error code once per process, defining the same code twice panics. The returned
+// Handler is also an error: errors.Is matches it, and every handler derived from it with the WithX methods,
+// against each other. Its message is resolved from the message bundles by key when rendered, see AddMessages.
+func Define(code int, statusCode int, key string) Handler {
+	definitionLock.Lock()
+	defer definitionLock.Unlock()
+	if definitionMap == nil {
+		definitionMap = make(map[int]Handler)
+	}
+	if _, ok := definitionMap[code]; ok {
+		panic(fmt.Sprintf("response code %d already defined", code))
+	}
+	def := &Definition{Code: code, StatusCode: statusCode, Key: key}
+	r := &Response{
+		StatusCode: statusCode,
+		Code:       code,
+		Msg:        key,
+		MsgKey:     key,
+		Errs:       make([]error, 0),
+		Header:     make(map[string]string),
+		definition: def,
+	}
+	h := r.freeze()
+	definitionMap[code] = h
+	return h
+}
+
+// Lookup returns the handler defined for code
+func Lookup(code int) (Handler, bool) {
+	definitionLock.RLock()
+	defer definitionLock.RUnlock()
+	h, ok := definitionMap[code]
+	return h, ok
+}
+
+// Definitions returns every defined business error ordered by code, e.g. to publish the error catalogue
+func Definitions() []Definition {
+	definitionLock.RLock()
+	defer definitionLock.RUnlock()
+	ans := make([]Definition, 0, len(definitionMap))
+	for _, h := range definitionMap {
+		ans = append(ans, *h().definition)
+	}
+	sort.Slice(ans, func(i, j int) bool {
+		return ans[i].Code < ans[j].Code
+	})
+	return ans
+}
@@ -0,0 +1,61 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestDefine(t *testing.T) {
+	duplicate := Define(90001, http.StatusConflict, "test.duplicate")
+	missing := Define(90002, http.StatusNotFound, "test.missing")
+
+	err := fmt.Errorf("create order: %w", duplicate.WithData(1).WithErr(errors.New("unique key")))
+	if !errors.Is(err, duplicate) || errors.Is(err, missing) || errors.Is(err, OK) {
+		t.Fatalf("errors.Is mismatched %v", err)
+	}
+	if h, ok := Lookup(90001); !ok || h.StatusCode() != http.StatusConflict || h.Code() != 90001 {
+		t.Fatal("Lookup did not return the definition")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatal("defining a code twice did not panic")
+		}
+	}()
+	Define(90001, http.StatusConflict, "test.again")
+}
+
+func TestDefinitionsOrdered(t *testing.T) {
+	for _, code := range []int{90105, 90101, 90103, 90104, 90102} {
+		Define(code, http.StatusBadRequest, fmt.Sprintf("test.%d", code))
+	}
+	for i := 0; i < 5; i++ {
+		defs := Definitions()
+		for j := 1; j < len(defs); j++ {
+			if defs[j-1].Code >= defs[j].Code {
+				t.Fatalf("definitions not ordered by code: %v", defs)
+			}
+		}
+	}
+}
+
+func TestLocalize(t *testing.T) {
+	AddMessages("zh", map[string]string{"test.localize": "中文"})
+	AddMessages("en", map[string]string{"test.localize": "english"})
+	langs := make([]string, 1, 4)
+	langs[0] = "zh-CN"
+	if msg := Localize("test.localize", langs...); msg != "中文" {
+		t.Fatalf("got %q", msg)
+	}
+	if spare := langs[:2]; spare[1] != "" {
+		t.Fatalf("Localize wrote into the caller's slice: %v", spare)
+	}
+	if msg := Localize("test.localize", ParseAcceptLanguage("fr;q=0.9, de")...); msg != "english" {
+		t.Fatalf("got %q, want the default language", msg)
+	}
+	if msg := Localize("test.unknown"); msg != "test.unknown" {
+		t.Fatalf("got %q, want the key", msg)
+	}
+}
@@ -0,0 +1,85 @@
+package response
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// FieldError a serializable validation failure of one request field
+type FieldError struct {
+	Field   string `json:"field"`
+	Rule    string `json:"rule"`
+	Param   string `json:"param,omitempty"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return e.Message
+}
+
+// FieldErrors the field errors of a request, also usable as an error
+type FieldErrors []FieldError
+
+func (e FieldErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, fe := range e {
+		msgs = append(msgs, fe.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+// FieldErrorsOf extracts the field errors wrapped in err: validator.ValidationErrors as returned by gin binding,
+// FieldErrors or a single FieldError
+func FieldErrorsOf(err error) (FieldErrors, bool) {
+	var ves validator.ValidationErrors
+	if errors.As(err, &ves) {
+		ans := make(FieldErrors, 0, len(ves))
+		for _, ve := range ves {
+			ans = append(ans, NewFieldError(ve))
+		}
+		return ans, true
+	}
+	var fes FieldErrors
+	if errors.As(err, &fes) {
+		return fes, true
+	}
+	var fe FieldError
+	if errors.As(err, &fe) {
+		return FieldErrors{fe}, true
+	}
+	return nil, false
+}
+
+// NewFieldError converts a validator field error, Field is the namespace without the top level struct name.
+// The validator names fields after the Go struct fields, to report the json or form names instead register a tag
+// name func on gin's validator at startup, e.g.:
+//
+//	binding.Validator.Engine().(*validator.Validate).RegisterTagNameFunc(func(f reflect.StructField) string {
+//		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+//		if name == "-" {
+//			return ""
+//		}
+//		return name
+//	})
+func NewFieldError(ve validator.FieldError) FieldError {
+	field := ve.Namespace()
+	if idx := strings.Index(field, "."); idx >= 0 {
+		field = field[idx+1:]
+	}
+	if field == "" {
+		field = ve.Field()
+	}
+	msg := fmt.Sprintf("%s failed on the %s rule", field, ve.Tag())
+	if ve.Param() != "" {
+		msg = fmt.Sprintf("%s failed on the %s=%s rule", field, ve.Tag(), ve.Param())
+	}
+	return FieldError{
+		Field:   field,
+		Rule:    ve.Tag(),
+		Param:   ve.Param(),
+		Message: msg,
+	}
+}
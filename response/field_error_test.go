@@ -0,0 +1,59 @@
+package response
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+)
+
+type fieldErrorAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type fieldErrorForm struct {
+	Name    string            `json:"name" validate:"required"`
+	Age     int               `json:"age" validate:"gte=18"`
+	Address fieldErrorAddress `json:"address"`
+}
+
+func fieldErrorsOf(t *testing.T, v *validator.Validate) FieldErrors {
+	t.Helper()
+	fes, ok := FieldErrorsOf(v.Struct(fieldErrorForm{Age: 1}))
+	if !ok {
+		t.Fatal("no field errors")
+	}
+	return fes
+}
+
+func TestNewFieldError(t *testing.T) {
+	fes := fieldErrorsOf(t, validator.New())
+	want := FieldErrors{
+		{Field: "Name", Rule: "required", Message: "Name failed on the required rule"},
+		{Field: "Age", Rule: "gte", Param: "18", Message: "Age failed on the gte=18 rule"},
+		{Field: "Address.City", Rule: "required", Message: "Address.City failed on the required rule"},
+	}
+	if !reflect.DeepEqual(fes, want) {
+		t.Fatalf("got %+v", fes)
+	}
+}
+
+func TestNewFieldErrorTagName(t *testing.T) {
+	v := validator.New()
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
+	fes := fieldErrorsOf(t, v)
+	var fields []string
+	for _, fe := range fes {
+		fields = append(fields, fe.Field)
+	}
+	if got := strings.Join(fields, " "); got != "name age address.city" {
+		t.Fatalf("got %q", got)
+	}
+}
@@ -0,0 +1,102 @@
+package response
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var (
+	// DefaultLanguage is used when none of the requested languages has a message for the key
+	DefaultLanguage = "en"
+
+	messageLock sync.RWMutex
+	messageMap  map[string]map[string]string
+)
+
+// AddMessages merges a message bundle of lang, keyed by the keys given to Define
+func AddMessages(lang string, messages map[string]string) {
+	messageLock.Lock()
+	defer messageLock.Unlock()
+	if messageMap == nil {
+		messageMap = make(map[string]map[string]string)
+	}
+	lang = strings.ToLower(lang)
+	bundle, ok := messageMap[lang]
+	if !ok {
+		bundle = make(map[string]string, len(messages))
+		messageMap[lang] = bundle
+	}
+	for k, v := range messages {
+		bundle[k] = v
+	}
+}
+
+// Localize resolves key in the first language that has it, trying "zh" after "zh-CN" and DefaultLanguage last.
+// The key itself is returned when no bundle has it.
+func Localize(key string, langs ...string) string {
+	messageLock.RLock()
+	defer messageLock.RUnlock()
+	for _, lang := range langs {
+		if msg, ok := localize(key, lang); ok {
+			return msg
+		}
+	}
+	if msg, ok := localize(key, DefaultLanguage); ok {
+		return msg
+	}
+	return key
+}
+
+// localize looks key up in the bundle of lang, then of its base language
+func localize(key string, lang string) (string, bool) {
+	lang = strings.ToLower(lang)
+	if msg, ok := messageMap[lang][key]; ok {
+		return msg, true
+	}
+	if idx := strings.IndexAny(lang, "-_"); idx > 0 {
+		if msg, ok := messageMap[lang[:idx]][key]; ok {
+			return msg, true
+		}
+	}
+	return "", false
+}
+
+// ParseAcceptLanguage returns the languages of an Accept-Language header ordered by quality
+func ParseAcceptLanguage(header string) []string {
+	type tag struct {
+		lang string
+		q    float64
+	}
+	tags := make([]tag, 0)
+	for _, part := range strings.Split(header, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		t := tag{lang: part, q: 1}
+		if idx := strings.Index(part, ";"); idx >= 0 {
+			t.lang = strings.TrimSpace(part[:idx])
+			param := strings.TrimSpace(part[idx+1:])
+			if strings.HasPrefix(param, "q=") {
+				q, err := strconv.ParseFloat(param[2:], 64)
+				if err == nil {
+					t.q = q
+				}
+			}
+		}
+		if t.lang == "" || t.lang == "*" || t.q <= 0 {
+			continue
+		}
+		tags = append(tags, t)
+	}
+	sort.SliceStable(tags, func(i, j int) bool {
+		return tags[i].q > tags[j].q
+	})
+	ans := make([]string, 0, len(tags))
+	for _, t := range tags {
+		ans = append(ans, t.lang)
+	}
+	return ans
+}
@@ -0,0 +1,67 @@
+package response
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// ProblemTypeBase prefixes the business code to build the problem type URI, e.g. "https://errors.example.com/"
+// gives "https://errors.example.com/10042". When empty the type is "about:blank".
+var ProblemTypeBase string
+
+// Problem a problem details object of RFC 9457, Extensions are rendered as top level members
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+	m["type"] = p.Type
+	m["title"] = p.Title
+	m["status"] = p.Status
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// Problem builds the problem details of r: Msg is the title, Code, Data and FieldErrors (if any) the "code",
+// "data" and "errors" extension members. With detail, Errs joined are the detail member, they often carry
+// driver or SQL messages and must not reach clients in production.
+func (r *Response) Problem(instance string, detail bool) *Problem {
+	typ := "about:blank"
+	if ProblemTypeBase != "" {
+		typ = ProblemTypeBase + strconv.Itoa(r.Code)
+	}
+	var errs []string
+	if detail {
+		errs = ErrMessages(r.Errs)
+	}
+	p := &Problem{
+		Type:       typ,
+		Title:      r.Msg,
+		Status:     r.StatusCode,
+		Detail:     strings.Join(errs, "\n"),
+		Instance:   instance,
+		Extensions: map[string]interface{}{"code": r.Code},
+	}
+	if r.Data != nil {
+		p.Extensions["data"] = r.Data
+	}
+	if len(r.FieldErrors) > 0 {
+		p.Extensions["errors"] = r.FieldErrors
+	}
+	return p
+}
@@ -1,6 +1,8 @@
 package response
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strings"
 )
@@ -22,222 +24,276 @@ const (
 	ContentTypeXML
 	ContentTypeYAML
 	ContentTypeHTML
+	ContentTypeProblemJSON
 )
 
 type Response struct {
-	StatusCode int               `json:"status_code"`
-	Header     map[string]string `json:"header"`
-	Code       int               `json:"code"`
-	Msg        string            `json:"msg"`
-	Data       interface{}       `json:"data"`
-	Errs       []error           `json:"errs"`
-	Type       ContentType       `json:"type"`
-	HTMLPath   string            `json:"html_path"`
+	StatusCode  int               `json:"status_code"`
+	Header      map[string]string `json:"header"`
+	Code        int               `json:"code"`
+	Msg         string            `json:"msg"`
+	Data        interface{}       `json:"data"`
+	Errs        []error           `json:"errs"`
+	Type        ContentType       `json:"type"`
+	HTMLPath    string            `json:"html_path"`
+	MsgKey      string            `json:"msg_key"`
+	FieldErrors FieldErrors       `json:"field_errors"`
+	definition  *Definition
 }
 
+// MarshalJSON renders Errs as their messages, an error value itself usually serializes to {}
+func (r Response) MarshalJSON() ([]byte, error) {
+	type response Response
+	return json.Marshal(struct {
+		response
+		Errs []string `json:"errs"`
+	}{
+		response: response(r),
+		Errs:     ErrMessages(r.Errs),
+	})
+}
+
+// ErrMessages returns the messages of errs, skipping nil entries
+func ErrMessages(errs []error) []string {
+	ans := make([]string, 0, len(errs))
+	for _, err := range errs {
+		if err != nil {
+			ans = append(ans, err.Error())
+		}
+	}
+	return ans
+}
+
+// Handler an immutable response, every WithX call copies it once and leaves h untouched. Calling h returns the
+// shared snapshot without copying, it must be treated as read-only; use Clone to get a response that may be modified.
 type Handler func() *Response
 
 func New() Handler {
-	return func() *Response {
-		return &Response{
-			Errs:   make([]error, 0),
-			Header: make(map[string]string),
-			Type:   ContentTypeJSON,
-		}
+	r := &Response{
+		Errs:   make([]error, 0),
+		Header: make(map[string]string),
+		Type:   ContentTypeJSON,
 	}
+	return r.freeze()
 }
 
 func NewWithStatusCode(statusCode int) Handler {
+	r := &Response{
+		StatusCode: statusCode,
+		Code:       statusCode,
+		Msg:        http.StatusText(statusCode),
+		Errs:       make([]error, 0),
+		Header:     make(map[string]string),
+	}
+	return r.freeze()
+}
+
+// with applies f to a shallow copy of the response of h and freezes it into a new handler. Header, Errs and
+// FieldErrors still belong to h, f must replace them instead of writing into them.
+func (h Handler) with(f func(a *Response)) Handler {
+	a := *h()
+	f(&a)
+	return a.freeze()
+}
+
+// freeze snapshots r, which must no longer be modified by the caller
+func (r *Response) freeze() Handler {
 	return func() *Response {
-		return &Response{
-			StatusCode: statusCode,
-			Code:       statusCode,
-			Msg:        http.StatusText(statusCode),
-			Errs:       make([]error, 0),
-			Header:     make(map[string]string),
-		}
+		return r
 	}
 }
 
+// Clone returns a deep copy of r, which can be modified without affecting the handler it came from
+func (r *Response) Clone() *Response {
+	a := *r
+	a.Header = make(map[string]string, len(r.Header))
+	for k, v := range r.Header {
+		a.Header[k] = v
+	}
+	a.Errs = append(make([]error, 0, len(r.Errs)), r.Errs...)
+	a.FieldErrors = append(FieldErrors(nil), r.FieldErrors...)
+	return &a
+}
+
 func (h Handler) WithStatusCode(statusCode int) Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.StatusCode = statusCode
-		return a
-	}
+	})
 }
 
 func (h Handler) WithHeader(key string, val string) Handler {
-	return func() *Response {
-		a := h()
-		a.Header[key] = val
-		return a
-	}
+	return h.with(func(a *Response) {
+		header := make(map[string]string, len(a.Header)+1)
+		for k, v := range a.Header {
+			header[k] = v
+		}
+		header[key] = val
+		a.Header = header
+	})
 }
 
 func (h Handler) WithCode(code int) Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Code = code
-		return a
-	}
+	})
 }
 
+// WithMsg sets a fixed message, it is no longer localized by MsgKey
 func (h Handler) WithMsg(msg string) Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Msg = msg
-		return a
-	}
+		a.MsgKey = ""
+	})
+}
+
+// WithMsgKey sets a message key localized from the message bundles when rendered
+func (h Handler) WithMsgKey(key string) Handler {
+	return h.with(func(a *Response) {
+		a.Msg = key
+		a.MsgKey = key
+	})
 }
 
 func (h Handler) WithData(data interface{}) Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Data = data
-		return a
-	}
+	})
 }
 
+// WithErr appends err, the field errors it wraps (e.g. binding validation errors) are appended to FieldErrors too
 func (h Handler) WithErr(err error) Handler {
-	return func() *Response {
-		a := h()
-		a.Errs = append(a.Errs, err)
-		return a
-	}
+	return h.with(func(a *Response) {
+		a.Errs = append(a.Errs[:len(a.Errs):len(a.Errs)], err)
+		if fes, ok := FieldErrorsOf(err); ok {
+			a.FieldErrors = append(a.FieldErrors[:len(a.FieldErrors):len(a.FieldErrors)], fes...)
+		}
+	})
+}
+
+func (h Handler) WithFieldErrors(errs ...FieldError) Handler {
+	return h.with(func(a *Response) {
+		a.FieldErrors = append(a.FieldErrors[:len(a.FieldErrors):len(a.FieldErrors)], errs...)
+	})
 }
 
 func (h Handler) WithHTMLPath(path string) Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.HTMLPath = path
-		return a
-	}
+	})
 }
 
 func (h Handler) JSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) IndentedJSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeIndentedJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) SecureJSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeSecureJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) JsonpJSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeJsonpJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) AsciiJSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeAsciiJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) PureJSON() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypePureJSON
-		return a
-	}
+	})
 }
 
 func (h Handler) MsgPack() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeMsgPack
-		return a
-	}
+	})
 }
 
 func (h Handler) ProtoBuf() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeProtoBuf
-		return a
-	}
+	})
 }
 
 func (h Handler) Redirect() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeRedirect
-		return a
-	}
+	})
 }
 
 func (h Handler) String() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeString
-		return a
-	}
+	})
 }
 
 func (h Handler) TOML() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeTOML
-		return a
-	}
+	})
 }
 
 func (h Handler) XML() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeXML
-		return a
-	}
+	})
 }
 
 func (h Handler) YAML() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeYAML
-		return a
-	}
+	})
 }
 
 func (h Handler) HTML() Handler {
-	return func() *Response {
-		a := h()
+	return h.with(func(a *Response) {
 		a.Type = ContentTypeHTML
-		return a
-	}
+	})
+}
+
+// Problem renders as application/problem+json, see Response.Problem
+func (h Handler) Problem() Handler {
+	return h.with(func(a *Response) {
+		a.Type = ContentTypeProblemJSON
+	})
 }
 
 func (h Handler) Error() string {
 	a := h()
-	var builder strings.Builder
-	limit := len(a.Errs) - 1
-	for idx, err := range a.Errs {
-		builder.WriteString(err.Error())
-		if idx < limit {
-			builder.WriteString("\n")
-		}
+	if len(a.Errs) == 0 && a.definition != nil {
+		return fmt.Sprintf("%d: %s", a.Code, a.Msg)
+	}
+	return strings.Join(ErrMessages(a.Errs), "\n")
+}
+
+// Is matches handlers derived from the same Define
+func (h Handler) Is(target error) bool {
+	t, ok := target.(Handler)
+	if !ok || t == nil {
+		return false
 	}
-	return builder.String()
+	def := h().definition
+	return def != nil && def == t().definition
+}
+
+// Definition returns the business error h was derived from, nil if it was not defined by Define
+func (h Handler) Definition() *Definition {
+	return h().definition
 }
 
 func (h Handler) StatusCode() int {
@@ -263,3 +319,7 @@ func (h Handler) Data() interface{} {
 func (h Handler) Errs() []error {
 	return h().Errs
 }
+
+func (h Handler) FieldErrors() FieldErrors {
+	return h().FieldErrors
+}
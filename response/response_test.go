@@ -0,0 +1,204 @@
+package response
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestHandlerIsolation(t *testing.T) {
+	base := OK.WithHeader("X-Base", "1")
+
+	if OK() != OK() {
+		t.Fatal("calling a handler copied its response")
+	}
+	r := OK().Clone()
+	r.Header["X-Leak"] = "1"
+	r.Errs = append(r.Errs, errors.New("leak"))
+	r.Msg = "leak"
+	if len(OK.Header()) != 0 || len(OK.Errs()) != 0 || OK.Msg() != http.StatusText(http.StatusOK) {
+		t.Fatalf("mutating a response leaked into OK: %+v", OK())
+	}
+	if h := OK.WithCode(1).Header(); len(h) != 0 {
+		t.Fatalf("mutating a response leaked into a derived handler: %v", h)
+	}
+
+	derived := base.WithHeader("X-Derived", "1")
+	if len(base.Header()) != 1 || len(derived.Header()) != 2 {
+		t.Fatalf("base %v, derived %v", base.Header(), derived.Header())
+	}
+
+	e1 := base.WithErr(errors.New("1"))
+	e2 := e1.WithErr(errors.New("2"))
+	e3 := e1.WithErr(errors.New("3"))
+	if e2.Error() != "1\n2" || e3.Error() != "1\n3" {
+		t.Fatalf("sibling handlers share errors: %q %q", e2.Error(), e3.Error())
+	}
+}
+
+func TestHandlerConcurrentUse(t *testing.T) {
+	h := OK.WithHeader("X-Base", "1")
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				r := h().Clone()
+				r.Header["X-Request"] = "1"
+				_ = h.WithMsg("x").Msg()
+			}
+		}()
+	}
+	wg.Wait()
+	if len(h.Header()) != 1 {
+		t.Fatalf("got %v", h.Header())
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// legacyHandler the closure chain handlers used before, every call rebuilds the response through the whole chain
+type legacyHandler func() *Response
+
+func legacyOK() legacyHandler {
+	return func() *Response {
+		return &Response{
+			StatusCode: http.StatusOK,
+			Code:       http.StatusOK,
+			Msg:        http.StatusText(http.StatusOK),
+			Errs:       make([]error, 0),
+			Header:     make(map[string]string),
+		}
+	}
+}
+
+func (h legacyHandler) withHeader(key string, val string) legacyHandler {
+	return func() *Response {
+		a := h()
+		a.Header[key] = val
+		return a
+	}
+}
+
+func (h legacyHandler) withMsg(msg string) legacyHandler {
+	return func() *Response {
+		a := h()
+		a.Msg = msg
+		return a
+	}
+}
+
+func (h legacyHandler) withData(data interface{}) legacyHandler {
+	return func() *Response {
+		a := h()
+		a.Data = data
+		return a
+	}
+}
+
+func (h legacyHandler) withErr(err error) legacyHandler {
+	return func() *Response {
+		a := h()
+		a.Errs = append(a.Errs, err)
+		return a
+	}
+}
+
+var (
+	errBenchmark = errors.New("benchmark")
+	// sink keeps the rendered responses on the heap, as a renderer handing them to gin does
+	sink *Response
+)
+
+// the chain shapes benchmarked: a status with data, as most handlers return, and a long error chain
+func legacyChain(long bool) legacyHandler {
+	h := legacyOK().withData(1)
+	if long {
+		h = h.withHeader("X-A", "1").withHeader("X-B", "1").withHeader("X-C", "1").withMsg("failed")
+		for i := 0; i < 4; i++ {
+			h = h.withErr(errBenchmark)
+		}
+	}
+	return h
+}
+
+func snapshotChain(long bool) Handler {
+	h := OK.WithData(1)
+	if long {
+		h = h.WithHeader("X-A", "1").WithHeader("X-B", "1").WithHeader("X-C", "1").WithMsg("failed")
+		for i := 0; i < 4; i++ {
+			h = h.WithErr(errBenchmark)
+		}
+	}
+	return h
+}
+
+func TestHandlerAccessorsDoNotAllocate(t *testing.T) {
+	h := snapshotChain(true)
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = h()
+		_ = h.StatusCode()
+		_ = h.Code()
+		_ = h.Header()
+		_ = h.Errs()
+		_ = h.Definition()
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocations", allocs)
+	}
+}
+
+// BenchmarkRender renders a chain built once, e.g. a package-level var, the way xgin.Wrap and the response
+// callbacks do: one response the renderer may modify and two accessors
+func BenchmarkRender(b *testing.B) {
+	for _, shape := range []struct {
+		name string
+		long bool
+	}{{"short", false}, {"long", true}} {
+		legacy, snapshot := legacyChain(shape.long), snapshotChain(shape.long)
+		b.Run(shape.name+"/legacy", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = legacy()
+				_ = legacy().StatusCode
+				_ = legacy().Code
+			}
+		})
+		b.Run(shape.name+"/snapshot", func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sink = snapshot().Clone()
+				_ = snapshot.StatusCode()
+				_ = snapshot.Code()
+			}
+		})
+	}
+}
+
+func TestResponseMarshalJSON(t *testing.T) {
+	r := BadRequest.WithErr(errors.New("boom")).WithErr(nil)()
+	for name, v := range map[string]interface{}{
+		"pointer": r,
+		"value":   *r,
+		"field":   struct{ R Response }{R: *r},
+		"slice":   []Response{*r},
+	} {
+		bytes, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !strings.Contains(string(bytes), `"errs":["boom"]`) {
+			t.Fatalf("%s: got %s", name, bytes)
+		}
+	}
+	if msg := BadRequest.WithErr(nil).WithErr(errors.New("boom")).Error(); msg != "boom" {
+		t.Fatalf("Error got %q", msg)
+	}
+	if p := r.Problem("/", true); p.Detail != "boom" {
+		t.Fatalf("problem detail got %q", p.Detail)
+	}
+}
@@ -2,6 +2,8 @@ package retry
 
 import (
 	"math"
+	"math/rand"
+	"sync"
 	"time"
 )
 
@@ -56,11 +58,12 @@ func (a *ArithmeticBackoff) Next(cnt int) (bool, time.Duration) {
 
 ////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
 
-// GeometricBackoff Geometric Sequence backoff
+// GeometricBackoff Geometric Sequence backoff, sleeps First * Q^cnt capped by Max, a zero Max means no cap
 type GeometricBackoff struct {
 	Count int
 	First time.Duration
 	Q     float64
+	Max   time.Duration
 }
 
 func NewGeometricBackoff(cnt int, first time.Duration, q float64) *GeometricBackoff {
@@ -68,5 +71,160 @@ func NewGeometricBackoff(cnt int, first time.Duration, q float64) *GeometricBack
 }
 
 func (g *GeometricBackoff) Next(cnt int) (bool, time.Duration) {
-	return cnt > g.Count, g.First * time.Duration(math.Pow(g.Q, float64(cnt)))
+	d := time.Duration(math.MaxInt64)
+	if f := float64(g.First) * math.Pow(g.Q, float64(cnt)); f < math.MaxInt64 {
+		d = time.Duration(f)
+	}
+	if g.Max > 0 && d > g.Max {
+		d = g.Max
+	}
+	return cnt > g.Count, d
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// jitter random source shared by the jittered backoffs, a nil Rand falls back to the global math/rand source
+type jitter struct {
+	Rand *rand.Rand
+	mu   sync.Mutex
+}
+
+func (j *jitter) setRand(r *rand.Rand) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.Rand = r
+}
+
+// between returns a random duration in [min, max]
+func (j *jitter) between(min time.Duration, max time.Duration) time.Duration {
+	if max <= min {
+		return min
+	}
+	n := int64(max-min) + 1
+	if n <= 0 {
+		n = math.MaxInt64
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.Rand == nil {
+		return min + time.Duration(rand.Int63n(n))
+	}
+	return min + time.Duration(j.Rand.Int63n(n))
+}
+
+// exponential returns min(max, base * 2^(cnt-1)), a non-positive max means no cap
+func exponential(cnt int, base time.Duration, max time.Duration) time.Duration {
+	limit := time.Duration(math.MaxInt64)
+	if max > 0 {
+		limit = max
+	}
+	if base <= 0 {
+		return 0
+	}
+	d := base
+	for i := 1; i < cnt; i++ {
+		if d > limit/2 {
+			return limit
+		}
+		d *= 2
+	}
+	if d > limit {
+		return limit
+	}
+	return d
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// FullJitterBackoff sleeps a random duration in [0, min(Max, Base * 2^(cnt-1))]
+type FullJitterBackoff struct {
+	jitter
+	Count int
+	Base  time.Duration
+	Max   time.Duration
+}
+
+func NewFullJitterBackoff(cnt int, base time.Duration, max time.Duration) *FullJitterBackoff {
+	return &FullJitterBackoff{Count: cnt, Base: base, Max: max}
+}
+
+// WithRand draws the jitter from r, e.g. a seeded source for reproducible tests
+func (f *FullJitterBackoff) WithRand(r *rand.Rand) *FullJitterBackoff {
+	f.setRand(r)
+	return f
+}
+
+func (f *FullJitterBackoff) Next(cnt int) (bool, time.Duration) {
+	return cnt > f.Count, f.between(0, exponential(cnt, f.Base, f.Max))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// EqualJitterBackoff keeps half of min(Max, Base * 2^(cnt-1)) and randomizes the other half
+type EqualJitterBackoff struct {
+	jitter
+	Count int
+	Base  time.Duration
+	Max   time.Duration
+}
+
+func NewEqualJitterBackoff(cnt int, base time.Duration, max time.Duration) *EqualJitterBackoff {
+	return &EqualJitterBackoff{Count: cnt, Base: base, Max: max}
+}
+
+// WithRand draws the jitter from r, e.g. a seeded source for reproducible tests
+func (e *EqualJitterBackoff) WithRand(r *rand.Rand) *EqualJitterBackoff {
+	e.setRand(r)
+	return e
+}
+
+func (e *EqualJitterBackoff) Next(cnt int) (bool, time.Duration) {
+	half := exponential(cnt, e.Base, e.Max) / 2
+	return cnt > e.Count, e.between(half, half*2)
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// DecorrelatedJitterBackoff sleeps a random duration in [Base, min(Max, previous sleep * 3)].
+// The previous sleep is kept on the instance and reset on the first count, goroutines sharing one instance
+// still get durations within [Base, Max].
+type DecorrelatedJitterBackoff struct {
+	jitter
+	Count int
+	Base  time.Duration
+	Max   time.Duration
+	prev  time.Duration
+}
+
+func NewDecorrelatedJitterBackoff(cnt int, base time.Duration, max time.Duration) *DecorrelatedJitterBackoff {
+	return &DecorrelatedJitterBackoff{Count: cnt, Base: base, Max: max}
+}
+
+// WithRand draws the jitter from r, e.g. a seeded source for reproducible tests
+func (d *DecorrelatedJitterBackoff) WithRand(r *rand.Rand) *DecorrelatedJitterBackoff {
+	d.setRand(r)
+	return d
+}
+
+func (d *DecorrelatedJitterBackoff) Next(cnt int) (bool, time.Duration) {
+	d.mu.Lock()
+	prev := d.prev
+	if cnt <= 1 || prev < d.Base {
+		prev = d.Base
+	}
+	d.mu.Unlock()
+
+	upper := time.Duration(math.MaxInt64)
+	if prev <= upper/3 {
+		upper = prev * 3
+	}
+	if d.Max > 0 && upper > d.Max {
+		upper = d.Max
+	}
+	sleep := d.between(d.Base, upper)
+
+	d.mu.Lock()
+	d.prev = sleep
+	d.mu.Unlock()
+	return cnt > d.Count, sleep
 }
@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+)
+
+func TestJitterBackoffBounds(t *testing.T) {
+	const (
+		base = 10 * time.Millisecond
+		max  = 300 * time.Millisecond
+	)
+	cases := []struct {
+		name    string
+		backoff IBackoff
+		bounds  func(cnt int, prev time.Duration) (time.Duration, time.Duration)
+	}{
+		{
+			name:    "full",
+			backoff: NewFullJitterBackoff(20, base, max).WithRand(rand.New(rand.NewSource(1))),
+			bounds: func(cnt int, _ time.Duration) (time.Duration, time.Duration) {
+				return 0, exponential(cnt, base, max)
+			},
+		},
+		{
+			name:    "equal",
+			backoff: NewEqualJitterBackoff(20, base, max).WithRand(rand.New(rand.NewSource(1))),
+			bounds: func(cnt int, _ time.Duration) (time.Duration, time.Duration) {
+				half := exponential(cnt, base, max) / 2
+				return half, half * 2
+			},
+		},
+		{
+			name:    "decorrelated",
+			backoff: NewDecorrelatedJitterBackoff(20, base, max).WithRand(rand.New(rand.NewSource(1))),
+			bounds: func(cnt int, prev time.Duration) (time.Duration, time.Duration) {
+				if cnt == 1 {
+					prev = base
+				}
+				if prev*3 > max {
+					return base, max
+				}
+				return base, prev * 3
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			for round := 0; round < 50; round++ {
+				var prev time.Duration
+				for cnt := 1; cnt <= 20; cnt++ {
+					stop, d := c.backoff.Next(cnt)
+					if stop {
+						t.Fatalf("stopped at count %d", cnt)
+					}
+					lo, hi := c.bounds(cnt, prev)
+					if d < lo || d > hi {
+						t.Fatalf("count %d: %v not in [%v, %v]", cnt, d, lo, hi)
+					}
+					prev = d
+				}
+				if stop, _ := c.backoff.Next(21); !stop {
+					t.Fatal("did not stop after Count")
+				}
+			}
+		})
+	}
+}
+
+func TestJitterBackoffSeeded(t *testing.T) {
+	sequence := func(b IBackoff) []time.Duration {
+		ans := make([]time.Duration, 0, 10)
+		for cnt := 1; cnt <= 10; cnt++ {
+			_, d := b.Next(cnt)
+			ans = append(ans, d)
+		}
+		return ans
+	}
+	seeded := map[string]func(seed int64) IBackoff{
+		"full": func(seed int64) IBackoff {
+			return NewFullJitterBackoff(10, time.Millisecond, time.Second).WithRand(rand.New(rand.NewSource(seed)))
+		},
+		"equal": func(seed int64) IBackoff {
+			return NewEqualJitterBackoff(10, time.Millisecond, time.Second).WithRand(rand.New(rand.NewSource(seed)))
+		},
+		"decorrelated": func(seed int64) IBackoff {
+			return NewDecorrelatedJitterBackoff(10, time.Millisecond, time.Second).WithRand(rand.New(rand.NewSource(seed)))
+		},
+	}
+	for name, newBackoff := range seeded {
+		t.Run(name, func(t *testing.T) {
+			a, b := sequence(newBackoff(7)), sequence(newBackoff(7))
+			for i := range a {
+				if a[i] != b[i] {
+					t.Fatalf("same seed gave %v and %v", a, b)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/lazyboon/boon/retry"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCircuitOpen is returned when the breaker is open, or half-open with all probe requests in flight.
+	ErrCircuitOpen = errors.New("breaker: circuit open")
+
+	// errPanic records a panicking task, it always counts as a failure
+	errPanic = errors.New("breaker: task panicked")
+)
+
+// OpenError is returned by Wrap while the breaker rejects calls, Last is the failure that opened it.
+// errors.Is matches both ErrCircuitOpen and Last.
+type OpenError struct {
+	Last error
+}
+
+func (e *OpenError) Error() string {
+	if e.Last == nil {
+		return ErrCircuitOpen.Error()
+	}
+	return fmt.Sprintf("%v, last failure: %v", ErrCircuitOpen, e.Last)
+}
+
+func (e *OpenError) Unwrap() []error {
+	if e.Last == nil {
+		return []error{ErrCircuitOpen}
+	}
+	return []error{ErrCircuitOpen, e.Last}
+}
+
+type State int8
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateClosed:
+		return "closed"
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type options struct {
+	consecutiveFailures int
+	failureRatio        float64
+	minRequests         int
+	interval            time.Duration
+	coolDown            time.Duration
+	halfOpenRequests    int
+	isFailure           func(err error) bool
+	onStateChange       func(from State, to State)
+}
+
+type Option func(o *options)
+
+// WithConsecutiveFailures opens the breaker after n failures in a row, 0 disables the check
+func WithConsecutiveFailures(n int) Option {
+	return func(o *options) {
+		o.consecutiveFailures = n
+	}
+}
+
+// WithFailureRatio opens the breaker once failures / requests reaches ratio, counted only after minRequests requests
+func WithFailureRatio(ratio float64, minRequests int) Option {
+	return func(o *options) {
+		o.failureRatio = ratio
+		o.minRequests = minRequests
+	}
+}
+
+// WithInterval resets the closed state counters every d, 0 keeps them until the state changes
+func WithInterval(d time.Duration) Option {
+	return func(o *options) {
+		o.interval = d
+	}
+}
+
+// WithCoolDown how long the breaker stays open before letting probe requests through
+func WithCoolDown(d time.Duration) Option {
+	return func(o *options) {
+		o.coolDown = d
+	}
+}
+
+// WithHalfOpenRequests number of probe requests allowed in half-open state, all of them must succeed to close again
+func WithHalfOpenRequests(n int) Option {
+	return func(o *options) {
+		o.halfOpenRequests = n
+	}
+}
+
+// WithIsFailure decides which errors count as failures, by default every non-nil error does
+func WithIsFailure(f func(err error) bool) Option {
+	return func(o *options) {
+		o.isFailure = f
+	}
+}
+
+// WithOnStateChange calls f on every state transition, f runs with the breaker locked and must not call back into it
+func WithOnStateChange(f func(from State, to State)) Option {
+	return func(o *options) {
+		o.onStateChange = f
+	}
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type counts struct {
+	requests             int
+	failures             int
+	consecutiveSuccesses int
+	consecutiveFailures  int
+}
+
+type Breaker struct {
+	opts       *options
+	mu         sync.Mutex
+	state      State
+	counts     counts
+	generation uint64
+	expiry     time.Time
+	lastErr    error
+}
+
+func New(opts ...Option) *Breaker {
+	o := &options{
+		consecutiveFailures: 5,
+		coolDown:            10 * time.Second,
+		halfOpenRequests:    1,
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	if o.halfOpenRequests <= 0 {
+		o.halfOpenRequests = 1
+	}
+	b := &Breaker{opts: o}
+	b.toNewGeneration(time.Now())
+	return b
+}
+
+func (b *Breaker) State() State {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, _ := b.currentState(time.Now())
+	return state
+}
+
+// Do runs task if the breaker allows it and records its outcome, returning ErrCircuitOpen without running it otherwise.
+// A panicking task counts as a failure and the panic is propagated.
+func (b *Breaker) Do(task func() error) error {
+	generation, err := b.before()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if e := recover(); e != nil {
+			b.after(generation, errPanic)
+			panic(e)
+		}
+	}()
+	err = task()
+	b.after(generation, err)
+	return err
+}
+
+// Wrap guards a retry task with the breaker, an open breaker stops the retry loop with an *OpenError
+func (b *Breaker) Wrap(task retry.ContextTaskHandler) retry.ContextTaskHandler {
+	return func(ctx context.Context, count int) error {
+		err := b.Do(func() error {
+			return task(ctx, count)
+		})
+		if errors.Is(err, ErrCircuitOpen) {
+			return retry.Permanent(&OpenError{Last: b.lastFailure()})
+		}
+		return err
+	}
+}
+
+// lastFailure the error of the last failure, kept until the breaker closes again
+func (b *Breaker) lastFailure() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastErr
+}
+
+func (b *Breaker) before() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	state, generation := b.currentState(time.Now())
+	if state == StateOpen {
+		return generation, ErrCircuitOpen
+	}
+	if state == StateHalfOpen && b.counts.requests >= b.opts.halfOpenRequests {
+		return generation, ErrCircuitOpen
+	}
+	b.counts.requests++
+	return generation, nil
+}
+
+func (b *Breaker) after(before uint64, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	state, generation := b.currentState(now)
+	// the outcome belongs to a previous generation, drop it
+	if generation != before {
+		return
+	}
+	failure := err != nil
+	if failure && err != errPanic && b.opts.isFailure != nil {
+		failure = b.opts.isFailure(err)
+	}
+	if failure {
+		b.lastErr = err
+		b.onFailure(state, now)
+	} else {
+		b.onSuccess(state, now)
+	}
+}
+
+func (b *Breaker) onSuccess(state State, now time.Time) {
+	b.counts.consecutiveSuccesses++
+	b.counts.consecutiveFailures = 0
+	if state == StateHalfOpen && b.counts.consecutiveSuccesses >= b.opts.halfOpenRequests {
+		b.setState(StateClosed, now)
+	}
+}
+
+func (b *Breaker) onFailure(state State, now time.Time) {
+	b.counts.failures++
+	b.counts.consecutiveFailures++
+	b.counts.consecutiveSuccesses = 0
+	switch state {
+	case StateClosed:
+		if b.readyToTrip() {
+			b.setState(StateOpen, now)
+		}
+	case StateHalfOpen:
+		b.setState(StateOpen, now)
+	}
+}
+
+func (b *Breaker) readyToTrip() bool {
+	if b.opts.consecutiveFailures > 0 && b.counts.consecutiveFailures >= b.opts.consecutiveFailures {
+		return true
+	}
+	if b.opts.failureRatio > 0 && b.counts.requests >= b.opts.minRequests && b.counts.requests > 0 {
+		return float64(b.counts.failures)/float64(b.counts.requests) >= b.opts.failureRatio
+	}
+	return false
+}
+
+func (b *Breaker) currentState(now time.Time) (State, uint64) {
+	switch b.state {
+	case StateClosed:
+		if !b.expiry.IsZero() && b.expiry.Before(now) {
+			b.toNewGeneration(now)
+		}
+	case StateOpen:
+		if b.expiry.Before(now) {
+			b.setState(StateHalfOpen, now)
+		}
+	}
+	return b.state, b.generation
+}
+
+func (b *Breaker) setState(state State, now time.Time) {
+	if b.state == state {
+		return
+	}
+	prev := b.state
+	b.state = state
+	if state == StateClosed {
+		b.lastErr = nil
+	}
+	b.toNewGeneration(now)
+	if b.opts.onStateChange != nil {
+		b.opts.onStateChange(prev, state)
+	}
+}
+
+func (b *Breaker) toNewGeneration(now time.Time) {
+	b.generation++
+	b.counts = counts{}
+	switch b.state {
+	case StateClosed:
+		if b.opts.interval > 0 {
+			b.expiry = now.Add(b.opts.interval)
+		} else {
+			b.expiry = time.Time{}
+		}
+	case StateOpen:
+		b.expiry = now.Add(b.opts.coolDown)
+	default:
+		b.expiry = time.Time{}
+	}
+}
@@ -0,0 +1,245 @@
+package breaker
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lazyboon/boon/retry"
+)
+
+const coolDown = 20 * time.Millisecond
+
+var errTask = errors.New("task failed")
+
+func fail() error {
+	return errTask
+}
+
+func succeed() error {
+	return nil
+}
+
+func TestStateMachine(t *testing.T) {
+	type step struct {
+		task      func() error
+		sleep     time.Duration
+		wantErr   error
+		wantState State
+	}
+	cases := []struct {
+		name  string
+		opts  []Option
+		steps []step
+	}{
+		{
+			name: "consecutive failures open",
+			opts: []Option{WithConsecutiveFailures(2), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: succeed, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+				{task: succeed, wantErr: ErrCircuitOpen, wantState: StateOpen},
+			},
+		},
+		{
+			name: "half-open probe success closes",
+			opts: []Option{WithConsecutiveFailures(1), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+				{sleep: 2 * coolDown, task: succeed, wantState: StateClosed},
+				{task: succeed, wantState: StateClosed},
+			},
+		},
+		{
+			name: "half-open probe failure reopens",
+			opts: []Option{WithConsecutiveFailures(1), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+				{sleep: 2 * coolDown, task: fail, wantErr: errTask, wantState: StateOpen},
+				{task: succeed, wantErr: ErrCircuitOpen, wantState: StateOpen},
+			},
+		},
+		{
+			name: "half-open needs every probe to succeed",
+			opts: []Option{WithConsecutiveFailures(1), WithCoolDown(coolDown), WithHalfOpenRequests(2)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+				{sleep: 2 * coolDown, task: succeed, wantState: StateHalfOpen},
+				{task: succeed, wantState: StateClosed},
+			},
+		},
+		{
+			name: "failure ratio waits for min requests",
+			opts: []Option{WithConsecutiveFailures(0), WithFailureRatio(0.5, 4), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: succeed, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+			},
+		},
+		{
+			name: "failure ratio below threshold stays closed",
+			opts: []Option{WithConsecutiveFailures(0), WithFailureRatio(0.8, 2), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: succeed, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: succeed, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+			},
+		},
+		{
+			name: "interval resets counts",
+			opts: []Option{WithConsecutiveFailures(2), WithInterval(coolDown), WithCoolDown(coolDown)},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{sleep: 2 * coolDown, task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateOpen},
+			},
+		},
+		{
+			name: "is failure ignores errors",
+			opts: []Option{WithConsecutiveFailures(1), WithIsFailure(func(err error) bool { return false })},
+			steps: []step{
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+				{task: fail, wantErr: errTask, wantState: StateClosed},
+			},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := New(c.opts...)
+			for i, s := range c.steps {
+				time.Sleep(s.sleep)
+				if err := b.Do(s.task); !errors.Is(err, s.wantErr) || (s.wantErr == nil && err != nil) {
+					t.Fatalf("step %d: err %v, want %v", i, err, s.wantErr)
+				}
+				if state := b.State(); state != s.wantState {
+					t.Fatalf("step %d: state %v, want %v", i, state, s.wantState)
+				}
+			}
+		})
+	}
+}
+
+func TestHalfOpenProbeLimit(t *testing.T) {
+	b := New(WithConsecutiveFailures(1), WithCoolDown(coolDown), WithHalfOpenRequests(2))
+	_ = b.Do(fail)
+	time.Sleep(2 * coolDown)
+
+	release := make(chan struct{})
+	var started, done sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			_ = b.Do(func() error {
+				started.Done()
+				<-release
+				return nil
+			})
+		}()
+	}
+	started.Wait()
+	if err := b.Do(succeed); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("third probe got err %v, want ErrCircuitOpen", err)
+	}
+	close(release)
+	done.Wait()
+	if state := b.State(); state != StateClosed {
+		t.Fatalf("state %v after both probes succeeded", state)
+	}
+}
+
+func TestOutcomeOfPreviousGenerationDropped(t *testing.T) {
+	b := New(WithConsecutiveFailures(1), WithCoolDown(coolDown))
+	release := make(chan struct{})
+	started := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = b.Do(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+	_ = b.Do(fail)
+	time.Sleep(2 * coolDown)
+	if state := b.State(); state != StateHalfOpen {
+		t.Fatalf("state %v, want half-open", state)
+	}
+	// the slow success started while closed, it must not close the half-open breaker
+	close(release)
+	<-done
+	if state := b.State(); state != StateHalfOpen {
+		t.Fatalf("state %v, want half-open", state)
+	}
+}
+
+func TestPanicCountsAsFailure(t *testing.T) {
+	b := New(WithConsecutiveFailures(1), WithCoolDown(coolDown))
+	_ = b.Do(fail)
+	time.Sleep(2 * coolDown)
+	func() {
+		defer func() {
+			if r := recover(); r != "boom" {
+				t.Fatalf("recovered %v, want the task panic", r)
+			}
+		}()
+		_ = b.Do(func() error {
+			panic("boom")
+		})
+	}()
+	if state := b.State(); state != StateOpen {
+		t.Fatalf("state %v after a panicking probe, want open", state)
+	}
+	time.Sleep(2 * coolDown)
+	if err := b.Do(succeed); err != nil {
+		t.Fatalf("probe after cool down got err %v", err)
+	}
+	if state := b.State(); state != StateClosed {
+		t.Fatalf("state %v, want closed", state)
+	}
+}
+
+func TestOnStateChange(t *testing.T) {
+	var changes []State
+	b := New(WithConsecutiveFailures(1), WithCoolDown(coolDown), WithOnStateChange(func(from State, to State) {
+		changes = append(changes, to)
+	}))
+	_ = b.Do(fail)
+	time.Sleep(2 * coolDown)
+	_ = b.Do(succeed)
+	want := []State{StateOpen, StateHalfOpen, StateClosed}
+	if len(changes) != len(want) {
+		t.Fatalf("changes %v, want %v", changes, want)
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Fatalf("changes %v, want %v", changes, want)
+		}
+	}
+}
+
+func TestWrapKeepsLastFailure(t *testing.T) {
+	b := New(WithConsecutiveFailures(1), WithCoolDown(time.Minute))
+	calls := 0
+	err := retry.DoContext(context.Background(), b.Wrap(func(ctx context.Context, count int) error {
+		calls++
+		return errTask
+	}), retry.WithBackoff(retry.NewZeroBackoff(5)))
+	if calls != 1 {
+		t.Fatalf("task ran %d times, the open breaker must stop retrying", calls)
+	}
+	var open *OpenError
+	if !errors.As(err, &open) || !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, errTask) {
+		t.Fatalf("got err %v, want an *OpenError matching ErrCircuitOpen and the task error", err)
+	}
+}
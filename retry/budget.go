@@ -0,0 +1,80 @@
+package retry
+
+import (
+	"sync"
+	"time"
+)
+
+const budgetBuckets = 10
+
+type budgetBucket struct {
+	index       int64
+	deposits    int
+	withdrawals int
+}
+
+// Budget caps retry amplification across goroutines. Within the sliding window, retries are allowed while
+// retries < Ratio * successful calls + MinPerSecond * window seconds. One budget is meant to be shared by every
+// call to the same dependency, see WithBudget.
+type Budget struct {
+	ratio        float64
+	minPerSecond int
+	window       time.Duration
+	width        time.Duration
+	mu           sync.Mutex
+	buckets      [budgetBuckets]budgetBucket
+}
+
+// NewBudget window defaults to 10 seconds when not positive
+func NewBudget(ratio float64, minPerSecond int, window time.Duration) *Budget {
+	if window <= 0 {
+		window = 10 * time.Second
+	}
+	width := window / budgetBuckets
+	if width <= 0 {
+		width = 1
+	}
+	return &Budget{
+		ratio:        ratio,
+		minPerSecond: minPerSecond,
+		window:       window,
+		width:        width,
+	}
+}
+
+// Deposit records a successful call
+func (b *Budget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.current(time.Now()).deposits++
+}
+
+// TryWithdraw takes one retry from the budget, it returns false when the budget is used up
+func (b *Budget) TryWithdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	current := b.current(now)
+	deposits, withdrawals := 0, 0
+	for i := range b.buckets {
+		if b.buckets[i].index > current.index-budgetBuckets {
+			deposits += b.buckets[i].deposits
+			withdrawals += b.buckets[i].withdrawals
+		}
+	}
+	limit := b.ratio*float64(deposits) + float64(b.minPerSecond)*b.window.Seconds()
+	if float64(withdrawals) >= limit {
+		return false
+	}
+	current.withdrawals++
+	return true
+}
+
+func (b *Budget) current(now time.Time) *budgetBucket {
+	index := now.UnixNano() / int64(b.width)
+	bucket := &b.buckets[index%budgetBuckets]
+	if bucket.index != index {
+		*bucket = budgetBucket{index: index}
+	}
+	return bucket
+}
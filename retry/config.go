@@ -0,0 +1,81 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	BackoffTypeZero               = "zero"
+	BackoffTypeAvg                = "avg"
+	BackoffTypeArithmetic         = "arithmetic"
+	BackoffTypeGeometric          = "geometric"
+	BackoffTypeFullJitter         = "full_jitter"
+	BackoffTypeEqualJitter        = "equal_jitter"
+	BackoffTypeDecorrelatedJitter = "decorrelated_jitter"
+)
+
+// BackoffConfig describes a backoff in config files, Base and Max are milliseconds.
+// Base is the duration of avg, the difference of arithmetic, the First of geometric and the base of the jitter
+// types. Factor is the ratio of geometric, the wait after attempt n is Base*Factor^n. Max caps geometric and the
+// jitter types, it is rejected for the other types.
+type BackoffConfig struct {
+	Type   string  `json:"type"`
+	Count  int     `json:"count"`
+	Base   uint    `json:"base"`
+	Factor float64 `json:"factor"`
+	Max    uint    `json:"max"`
+}
+
+func (c *BackoffConfig) Build() (IBackoff, error) {
+	if c.Count < 0 {
+		return nil, errors.New("backoff config error: count can't be negative")
+	}
+	base := time.Duration(c.Base) * time.Millisecond
+	max := time.Duration(c.Max) * time.Millisecond
+	switch c.Type {
+	case BackoffTypeZero, BackoffTypeAvg, BackoffTypeArithmetic:
+		if c.Max != 0 {
+			return nil, fmt.Errorf("backoff config error: %s doesn't support max", c.Type)
+		}
+	case BackoffTypeGeometric, BackoffTypeFullJitter, BackoffTypeEqualJitter, BackoffTypeDecorrelatedJitter:
+		if c.Max != 0 && c.Max < c.Base {
+			return nil, fmt.Errorf("backoff config error: %s max can't be less than base", c.Type)
+		}
+	}
+	switch c.Type {
+	case BackoffTypeZero:
+		return NewZeroBackoff(c.Count), nil
+	case BackoffTypeAvg:
+		return NewAvgBackoff(c.Count, base), nil
+	case BackoffTypeArithmetic:
+		return NewArithmeticBackoff(c.Count, base), nil
+	case BackoffTypeGeometric:
+		if c.Base == 0 {
+			return nil, errors.New("backoff config error: geometric base must be greater than 0")
+		}
+		if c.Factor <= 0 {
+			return nil, errors.New("backoff config error: geometric factor must be greater than 0")
+		}
+		b := NewGeometricBackoff(c.Count, base, c.Factor)
+		b.Max = max
+		return b, nil
+	case BackoffTypeFullJitter, BackoffTypeEqualJitter, BackoffTypeDecorrelatedJitter:
+		if c.Base == 0 {
+			return nil, fmt.Errorf("backoff config error: %s base must be greater than 0", c.Type)
+		}
+		switch c.Type {
+		case BackoffTypeFullJitter:
+			return NewFullJitterBackoff(c.Count, base, max), nil
+		case BackoffTypeEqualJitter:
+			return NewEqualJitterBackoff(c.Count, base, max), nil
+		default:
+			return NewDecorrelatedJitterBackoff(c.Count, base, max), nil
+		}
+	case "":
+		return nil, errors.New("backoff config error: type must provide")
+	default:
+		return nil, fmt.Errorf("backoff config error: type %q unknown", c.Type)
+	}
+}
@@ -0,0 +1,68 @@
+package retry
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestBackoffConfigGeometric(t *testing.T) {
+	cases := []struct {
+		name   string
+		config BackoffConfig
+		want   map[int]time.Duration
+	}{
+		{
+			name:   "fractional factor",
+			config: BackoffConfig{Type: BackoffTypeGeometric, Count: 4, Base: 100, Factor: 0.5},
+			want:   map[int]time.Duration{1: 50 * time.Millisecond, 2: 25 * time.Millisecond},
+		},
+		{
+			name:   "non integer factor",
+			config: BackoffConfig{Type: BackoffTypeGeometric, Count: 4, Base: 100, Factor: 1.5},
+			want:   map[int]time.Duration{1: 150 * time.Millisecond, 2: 225 * time.Millisecond},
+		},
+		{
+			name:   "max caps",
+			config: BackoffConfig{Type: BackoffTypeGeometric, Count: 100, Base: 1000, Factor: 2, Max: 60000},
+			want:   map[int]time.Duration{5: 32 * time.Second, 10: time.Minute, 40: time.Minute, 100: time.Minute},
+		},
+		{
+			name:   "overflow clamps",
+			config: BackoffConfig{Type: BackoffTypeGeometric, Count: 100, Base: 1000, Factor: 2},
+			want:   map[int]time.Duration{40: math.MaxInt64, 100: math.MaxInt64},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := c.config.Build()
+			if err != nil {
+				t.Fatal(err)
+			}
+			for cnt, want := range c.want {
+				if _, d := b.Next(cnt); d != want {
+					t.Fatalf("attempt %d waits %v, want %v", cnt, d, want)
+				}
+			}
+		})
+	}
+}
+
+func TestBackoffConfigInvalid(t *testing.T) {
+	cases := []BackoffConfig{
+		{},
+		{Type: "unknown"},
+		{Type: BackoffTypeAvg, Count: -1, Base: 10},
+		{Type: BackoffTypeAvg, Count: 1, Base: 10, Max: 100},
+		{Type: BackoffTypeGeometric, Count: 1, Base: 0, Factor: 2},
+		{Type: BackoffTypeGeometric, Count: 1, Base: 10, Factor: 0},
+		{Type: BackoffTypeGeometric, Count: 1, Base: 100, Factor: 2, Max: 10},
+		{Type: BackoffTypeFullJitter, Count: 1, Base: 0},
+		{Type: BackoffTypeEqualJitter, Count: 1, Base: 100, Max: 10},
+	}
+	for _, c := range cases {
+		if _, err := c.Build(); err == nil {
+			t.Errorf("config %+v built without error", c)
+		}
+	}
+}
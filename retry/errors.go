@@ -0,0 +1,64 @@
+package retry
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrMaxElapsed is the Cause of *Error when waiting for the next attempt would exceed WithMaxElapsed.
+	ErrMaxElapsed = errors.New("max elapsed time exceeded")
+
+	// ErrAttemptTimeout is the Cause of *Error when a single attempt ran out of WithAttemptTimeout.
+	ErrAttemptTimeout = errors.New("attempt timed out")
+)
+
+// Error is returned when retrying was interrupted before the task succeeded.
+// Cause is the reason retrying stopped, Last is the error of the last attempt.
+// errors.Is and errors.As match against both of them.
+type Error struct {
+	Cause error
+	Last  error
+}
+
+func (e *Error) Error() string {
+	if e.Last == nil {
+		return fmt.Sprintf("retry: %v", e.Cause)
+	}
+	return fmt.Sprintf("retry: %v, last error: %v", e.Cause, e.Last)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Cause
+}
+
+func (e *Error) Is(target error) bool {
+	return errors.Is(e.Cause, target) || (e.Last != nil && errors.Is(e.Last, target))
+}
+
+func (e *Error) As(target interface{}) bool {
+	return errors.As(e.Cause, target) || (e.Last != nil && errors.As(e.Last, target))
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// PermanentError marks an error that must not be retried, Do stops right away and returns Err.
+type PermanentError struct {
+	Err error
+}
+
+// Permanent wraps err so that retrying stops immediately, a nil err stays nil.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &PermanentError{Err: err}
+}
+
+func (p *PermanentError) Error() string {
+	return p.Err.Error()
+}
+
+func (p *PermanentError) Unwrap() error {
+	return p.Err
+}
@@ -0,0 +1,164 @@
+package retry
+
+import (
+	"context"
+	"time"
+)
+
+const defaultHedgeLimit = 2
+
+// WithHedgeLimit caps the attempts Hedge runs at the same time, defaults to 2
+func WithHedgeLimit(n int) Option {
+	return func(o *options) {
+		o.hedgeLimit = n
+	}
+}
+
+type hedgeOutcome[T any] struct {
+	count int
+	val   T
+	err   error
+}
+
+// Hedge like DoContext, but does not wait for a slow attempt to fail: the backoff duration after attempt n is
+// the delay before attempt n+1 is started next to the ones still running, up to WithHedgeLimit at a time.
+// The first successful attempt wins and the context of every other attempt is cancelled.
+// A failed attempt frees its slot, the next one still starts on schedule. Without a backoff only one attempt runs.
+func Hedge(ctx context.Context, task ContextTaskHandler, opts ...Option) error {
+	_, err := HedgeValue(ctx, func(ctx context.Context, count int) (struct{}, error) {
+		return struct{}{}, task(ctx, count)
+	}, opts...)
+	return err
+}
+
+// HedgeValue like Hedge, returning the value of the winning attempt
+func HedgeValue[T any](ctx context.Context, task ValueTaskHandler[T], opts ...Option) (T, error) {
+	o := newOptions(opts...)
+	r := newResult()
+	val, err := hedge(ctx, task, o, r)
+	o.finish(r, err)
+	return val, err
+}
+
+func hedge[T any](ctx context.Context, task ValueTaskHandler[T], o *options, r *Result) (T, error) {
+	var zero T
+	limit := o.hedgeLimit
+	if limit <= 0 {
+		limit = defaultHedgeLimit
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	outcomes := make(chan hedgeOutcome[T])
+	launched, inflight := 0, 0
+	launch := func() {
+		launched++
+		inflight++
+		r.Attempts = launched
+		go func(count int) {
+			var val T
+			err := o.attempt(ctx, func(ctx context.Context) error {
+				var err error
+				val, err = task(ctx, count)
+				return err
+			})
+			select {
+			case outcomes <- hedgeOutcome[T]{count: count, val: val, err: err}:
+			case <-ctx.Done():
+			}
+		}(launched)
+	}
+
+	// schedule the next attempt, stopped once the backoff, the max elapsed time or the budget allow no more attempts
+	var (
+		timer     *time.Timer
+		timerC    <-chan time.Time
+		nextAt    time.Time
+		stopped   bool
+		stopCause error
+		pending   bool
+	)
+	defer func() {
+		if timer != nil {
+			timer.Stop()
+		}
+	}()
+	schedule := func() {
+		timerC = nil
+		if o.backoff == nil {
+			stopped = true
+			return
+		}
+		stop, d := o.backoff.Next(launched)
+		if stop {
+			stopped = true
+			return
+		}
+		if o.exceedsMaxElapsed(r, d) {
+			stopped = true
+			stopCause = ErrMaxElapsed
+			return
+		}
+		if o.budget != nil && !o.budget.TryWithdraw() {
+			stopped = true
+			return
+		}
+		nextAt = time.Now().Add(d)
+		if timer == nil {
+			timer = time.NewTimer(d)
+		} else {
+			timer.Reset(d)
+		}
+		timerC = timer.C
+	}
+	start := func() {
+		pending = false
+		launch()
+		schedule()
+	}
+
+	var lastErr error
+	start()
+	for {
+		select {
+		case <-ctx.Done():
+			return zero, &Error{Cause: ctx.Err(), Last: lastErr}
+		case <-timerC:
+			timerC = nil
+			pending = true
+			if inflight < limit {
+				start()
+			}
+		case out := <-outcomes:
+			inflight--
+			if out.err == nil {
+				if o.budget != nil {
+					o.budget.Deposit()
+				}
+				return out.val, nil
+			}
+			r.Errs = append(r.Errs, out.err)
+			lastErr = out.err
+			if permanent, ok := o.permanent(out.err); ok {
+				return zero, permanent
+			}
+			if stopped && !pending && inflight == 0 {
+				if stopCause != nil {
+					return zero, &Error{Cause: stopCause, Last: out.err}
+				}
+				return zero, out.err
+			}
+			if o.onRetry != nil {
+				var wait time.Duration
+				if timerC != nil {
+					wait = time.Until(nextAt)
+				}
+				o.onRetry(out.count, out.err, wait)
+			}
+			if pending {
+				start()
+			}
+		}
+	}
+}
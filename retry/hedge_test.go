@@ -0,0 +1,128 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// checkNoLeak fails if goroutines started by the test are still running shortly after it returns
+func checkNoLeak(t *testing.T) {
+	t.Helper()
+	before := runtime.NumGoroutine()
+	t.Cleanup(func() {
+		deadline := time.Now().Add(time.Second)
+		for runtime.NumGoroutine() > before {
+			if time.Now().After(deadline) {
+				t.Errorf("goroutines leaked: %d before, %d after", before, runtime.NumGoroutine())
+				return
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	})
+}
+
+func TestHedgeSlowAttemptOvertaken(t *testing.T) {
+	checkNoLeak(t)
+	cancelled := make(chan error, 1)
+	val, err := HedgeValue(context.Background(), func(ctx context.Context, count int) (int, error) {
+		if count == 1 {
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return 0, ctx.Err()
+		}
+		return count, nil
+	}, WithBackoff(NewAvgBackoff(3, 10*time.Millisecond)))
+	if err != nil || val != 2 {
+		t.Fatalf("got %d, %v, want the second attempt to win", val, err)
+	}
+	select {
+	case err := <-cancelled:
+		if !errors.Is(err, context.Canceled) {
+			t.Fatalf("slow attempt ctx err %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("context of the slow attempt was not cancelled")
+	}
+}
+
+func TestHedgeLimit(t *testing.T) {
+	checkNoLeak(t)
+	var running, peak int32
+	r := &Result{}
+	err := Hedge(context.Background(), func(ctx context.Context, count int) error {
+		n := atomic.AddInt32(&running, 1)
+		defer atomic.AddInt32(&running, -1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		return fmt.Errorf("attempt %d", count)
+	}, WithBackoff(NewAvgBackoff(5, time.Millisecond)), WithHedgeLimit(2), WithResult(r))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if peak != 2 {
+		t.Fatalf("peak concurrency %d, want 2", peak)
+	}
+	if r.Attempts != 6 {
+		t.Fatalf("attempts %d, want 6", r.Attempts)
+	}
+}
+
+func TestHedgeAllFail(t *testing.T) {
+	checkNoLeak(t)
+	r := &Result{}
+	err := Hedge(context.Background(), func(ctx context.Context, count int) error {
+		// later attempts fail later, so the last attempt reports last
+		time.Sleep(time.Duration(count) * 5 * time.Millisecond)
+		return fmt.Errorf("attempt %d", count)
+	}, WithBackoff(NewAvgBackoff(2, time.Millisecond)), WithHedgeLimit(3), WithResult(r))
+	if err == nil || err.Error() != "attempt 3" {
+		t.Fatalf("got err %v, want the error of the last attempt", err)
+	}
+	if r.Attempts != 3 || len(r.Errs) != 3 {
+		t.Fatalf("result %+v, want 3 attempts and errors", r)
+	}
+}
+
+func TestHedgePermanent(t *testing.T) {
+	checkNoLeak(t)
+	errStop := errors.New("stop")
+	r := &Result{}
+	start := time.Now()
+	err := Hedge(context.Background(), func(ctx context.Context, count int) error {
+		return Permanent(errStop)
+	}, WithBackoff(NewAvgBackoff(5, 50*time.Millisecond)), WithResult(r))
+	if err != errStop {
+		t.Fatalf("got err %v, want the unwrapped permanent error", err)
+	}
+	if r.Attempts != 1 || time.Since(start) > 40*time.Millisecond {
+		t.Fatalf("%d attempts in %v, want to stop right away", r.Attempts, time.Since(start))
+	}
+}
+
+func TestHedgeContextCancelled(t *testing.T) {
+	checkNoLeak(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(20*time.Millisecond, cancel)
+	start := time.Now()
+	err := Hedge(ctx, func(ctx context.Context, count int) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}, WithBackoff(NewAvgBackoff(100, 5*time.Millisecond)))
+	var retryErr *Error
+	if !errors.As(err, &retryErr) || !errors.Is(err, context.Canceled) {
+		t.Fatalf("got err %v, want an *Error caused by the cancellation", err)
+	}
+	if time.Since(start) > 500*time.Millisecond {
+		t.Fatalf("returned after %v", time.Since(start))
+	}
+}
@@ -0,0 +1,62 @@
+package retry
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+// Result statistics of one retry call, see WithResult
+type Result struct {
+	Attempts int
+	Elapsed  time.Duration
+	Errs     Errors
+	start    time.Time
+}
+
+func newResult() *Result {
+	return &Result{start: time.Now()}
+}
+
+// Err returns all collected errors joined, nil if there were none
+func (r *Result) Err() error {
+	if len(r.Errs) == 0 {
+		return nil
+	}
+	return r.Errs
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+// Errors joined attempt errors, errors.Is and errors.As match against any of them
+type Errors []error
+
+func (e Errors) Error() string {
+	var builder strings.Builder
+	limit := len(e) - 1
+	for idx, err := range e {
+		builder.WriteString(err.Error())
+		if idx < limit {
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+func (e Errors) Is(target error) bool {
+	for _, err := range e {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e Errors) As(target interface{}) bool {
+	for _, err := range e {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,21 @@
 package retry
 
-import "time"
+import (
+	"context"
+	"errors"
+	"time"
+)
 
 type options struct {
-	backoff IBackoff
+	backoff        IBackoff
+	retryIf        func(err error) bool
+	onRetry        func(attempt int, err error, wait time.Duration)
+	onGiveUp       func(attempt int, err error)
+	result         *Result
+	budget         *Budget
+	hedgeLimit     int
+	maxElapsed     time.Duration
+	attemptTimeout time.Duration
 }
 
 type Option func(o *options)
@@ -14,26 +26,189 @@ func WithBackoff(backoff IBackoff) Option {
 	}
 }
 
-type TaskHandler func(count int) error
+// WithRetryIf only retries errors for which f returns true, any other error is returned right away
+func WithRetryIf(f func(err error) bool) Option {
+	return func(o *options) {
+		o.retryIf = f
+	}
+}
 
-func Do(task TaskHandler, opts ...Option) error {
+// WithOnRetry calls f after every failed attempt that is going to be retried, wait is the backoff before the next one
+func WithOnRetry(f func(attempt int, err error, wait time.Duration)) Option {
+	return func(o *options) {
+		o.onRetry = f
+	}
+}
+
+// WithOnGiveUp calls f once when retrying stops without success, err is the error returned to the caller
+func WithOnGiveUp(f func(attempt int, err error)) Option {
+	return func(o *options) {
+		o.onGiveUp = f
+	}
+}
+
+// WithResult fills r with the attempt count, elapsed time and errors of the call when it returns
+func WithResult(r *Result) Option {
+	return func(o *options) {
+		o.result = r
+	}
+}
+
+// WithBudget shares budget between calls, once it is used up the last error is returned without retrying
+func WithBudget(budget *Budget) Option {
+	return func(o *options) {
+		o.budget = budget
+	}
+}
+
+// WithMaxElapsed gives up with ErrMaxElapsed once waiting for the next attempt would exceed d since the first one
+func WithMaxElapsed(d time.Duration) Option {
+	return func(o *options) {
+		o.maxElapsed = d
+	}
+}
+
+// WithAttemptTimeout runs every attempt under its own context deadline of d, an attempt failing because of it
+// is reported as an *Error with ErrAttemptTimeout as Cause and is still retried
+func WithAttemptTimeout(d time.Duration) Option {
+	return func(o *options) {
+		o.attemptTimeout = d
+	}
+}
+
+func newOptions(opts ...Option) *options {
 	o := &options{}
 	for _, opt := range opts {
 		opt(o)
 	}
+	return o
+}
+
+////////////////////////////////////////////////////////////////////////////////////////////////////////////////////////
+
+type TaskHandler func(count int) error
+
+type ContextTaskHandler func(ctx context.Context, count int) error
+
+func Do(task TaskHandler, opts ...Option) error {
+	return DoContext(context.Background(), func(_ context.Context, count int) error {
+		return task(count)
+	}, opts...)
+}
+
+// DoContext like Do, but stops waiting as soon as ctx is done. When the context ends the retry, the returned *Error
+// carries both the context error and the last task error.
+// Errors wrapped by Permanent, or rejected by WithRetryIf, stop retrying and are returned unwrapped.
+func DoContext(ctx context.Context, task ContextTaskHandler, opts ...Option) error {
+	o := newOptions(opts...)
+	r := newResult()
+	err := o.do(ctx, task, r)
+	o.finish(r, err)
+	return err
+}
+
+func (o *options) do(ctx context.Context, task ContextTaskHandler, r *Result) error {
 	var err error
 	for i := 1; ; i++ {
-		err = task(i)
+		r.Attempts = i
+		count := i
+		err = o.attempt(ctx, func(ctx context.Context) error {
+			return task(ctx, count)
+		})
 		if err == nil {
+			if o.budget != nil {
+				o.budget.Deposit()
+			}
 			return nil
 		}
+		r.Errs = append(r.Errs, err)
+		if permanent, ok := o.permanent(err); ok {
+			return permanent
+		}
+		var sleep time.Duration
 		if o.backoff != nil {
-			stop, sleep := o.backoff.Next(i)
+			var stop bool
+			stop, sleep = o.backoff.Next(i)
 			if stop {
 				break
 			}
-			time.Sleep(sleep)
 		}
+		if o.exceedsMaxElapsed(r, sleep) {
+			return &Error{Cause: ErrMaxElapsed, Last: err}
+		}
+		if o.budget != nil && !o.budget.TryWithdraw() {
+			break
+		}
+		if o.onRetry != nil {
+			o.onRetry(i, err, sleep)
+		}
+		if cause := wait(ctx, sleep); cause != nil {
+			return &Error{Cause: cause, Last: err}
+		}
+	}
+	return err
+}
+
+// finish reports the outcome of a call to the result and give up hooks
+func (o *options) finish(r *Result, err error) {
+	r.Elapsed = time.Since(r.start)
+	if err != nil && o.onGiveUp != nil {
+		o.onGiveUp(r.Attempts, err)
+	}
+	if o.result != nil {
+		*o.result = *r
+	}
+}
+
+// attempt runs one attempt under the attempt timeout, if any
+func (o *options) attempt(ctx context.Context, f func(ctx context.Context) error) error {
+	if o.attemptTimeout <= 0 {
+		return f(ctx)
+	}
+	attemptCtx, cancel := context.WithTimeout(ctx, o.attemptTimeout)
+	defer cancel()
+	err := f(attemptCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return &Error{Cause: ErrAttemptTimeout, Last: err}
 	}
 	return err
 }
+
+// exceedsMaxElapsed reports whether waiting another d would pass the max elapsed time
+func (o *options) exceedsMaxElapsed(r *Result, d time.Duration) bool {
+	return o.maxElapsed > 0 && time.Since(r.start)+d > o.maxElapsed
+}
+
+// permanent reports whether err must not be retried, returning the cause to hand back to the caller
+func (o *options) permanent(err error) (error, bool) {
+	var p *PermanentError
+	if errors.As(err, &p) {
+		return p.Err, true
+	}
+	if o.retryIf != nil && !o.retryIf(err) {
+		return err, true
+	}
+	return nil, false
+}
+
+// wait sleeps for d, returning early with the context error if ctx is done, or immediately with
+// context.DeadlineExceeded if sleeping would outlast the ctx deadline.
+func wait(ctx context.Context, d time.Duration) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if d <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && time.Now().Add(d).After(deadline) {
+		return context.DeadlineExceeded
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
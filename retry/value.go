@@ -0,0 +1,19 @@
+package retry
+
+import "context"
+
+type ValueTaskHandler[T any] func(ctx context.Context, count int) (T, error)
+
+// DoValue like DoContext, returning the value of the successful attempt, or the zero value of T on failure
+func DoValue[T any](ctx context.Context, task ValueTaskHandler[T], opts ...Option) (T, error) {
+	var ans T
+	err := DoContext(ctx, func(ctx context.Context, count int) error {
+		v, err := task(ctx, count)
+		if err != nil {
+			return err
+		}
+		ans = v
+		return nil
+	}, opts...)
+	return ans, err
+}
@@ -8,6 +8,7 @@ import (
 )
 
 var (
+	// ErrorCallback handles binding and validation errors instead of a bare 400, see xgin.Render
 	ErrorCallback func(ctx *gin.Context, err error)
 )
 
@@ -46,29 +47,30 @@ func bind(bindKey Key, val interface{}) gin.HandlerFunc {
 		case KeyUri:
 			err = ctx.ShouldBindUri(obj)
 		}
-		abort := func(err error) {
-			if ErrorCallback != nil {
-				ErrorCallback(ctx, err)
-				ctx.Abort()
-			} else {
-				ctx.AbortWithStatus(http.StatusBadRequest)
-			}
-		}
 		if err != nil {
-			abort(err)
+			abort(ctx, err)
 			return
 		}
 		if v, ok := obj.(Validator); ok {
 			err = v.Validate()
 		}
 		if err != nil {
-			abort(err)
+			abort(ctx, err)
 			return
 		}
 		ctx.Set(string(bindKey), obj)
 	}
 }
 
+func abort(ctx *gin.Context, err error) {
+	if ErrorCallback != nil {
+		ErrorCallback(ctx, err)
+		ctx.Abort()
+	} else {
+		ctx.AbortWithStatus(http.StatusBadRequest)
+	}
+}
+
 func JSON(val interface{}) gin.HandlerFunc {
 	return bind(KeyJSON, val)
 }
@@ -15,4 +15,5 @@ const (
 	KeyHeader        Key = "_lazyboon.bind.header.key"
 	KeyTOML          Key = "_lazyboon.bind.toml.key"
 	KeyUri           Key = "_lazyboon.bind.uri.key"
+	KeyHashIDParam   Key = "_lazyboon.bind.hashid_param.key"
 )
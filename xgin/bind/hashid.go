@@ -0,0 +1,51 @@
+package bind
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/lazyboon/boon/hashid"
+)
+
+// HashIDParam decodes the named path params, or query params when there is no such path param,
+// with the shared hashid encoder. The encoder is looked up per request, so routes can be registered
+// before hashid.InitHashID.
+func HashIDParam(names ...string) gin.HandlerFunc {
+	return hashIDParam(hashid.SharedEncoder, names)
+}
+
+// HashIDParamWith like HashIDParam, decoding with encoder. The numbers are kept by name under KeyHashIDParam,
+// a missing or invalid param aborts the request.
+func HashIDParamWith(encoder *hashid.Encoder, names ...string) gin.HandlerFunc {
+	return hashIDParam(func() *hashid.Encoder {
+		return encoder
+	}, names)
+}
+
+func hashIDParam(encoderOf func() *hashid.Encoder, names []string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		encoder := encoderOf()
+		params := make(map[string]int64)
+		if prev, ok := ctx.Get(string(KeyHashIDParam)); ok {
+			for k, v := range prev.(map[string]int64) {
+				params[k] = v
+			}
+		}
+		for _, name := range names {
+			val, ok := ctx.Params.Get(name)
+			if !ok {
+				val, ok = ctx.GetQuery(name)
+			}
+			if !ok {
+				abort(ctx, fmt.Errorf("hashid param %q missing", name))
+				return
+			}
+			num, err := encoder.Decode(val)
+			if err != nil {
+				abort(ctx, fmt.Errorf("hashid param %q: %w", name, err))
+				return
+			}
+			params[name] = num
+		}
+		ctx.Set(string(KeyHashIDParam), params)
+	}
+}
@@ -10,6 +10,12 @@ import (
 var (
 	BeforeResponseCallback func(ctx *gin.Context, handler response.Handler) (stop bool)
 	AfterResponseCallback  func(ctx *gin.Context, handler response.Handler)
+
+	// ProblemForErrors renders every 4xx and 5xx response as application/problem+json
+	ProblemForErrors bool
+
+	// ProblemDebugDetail adds the errors of a response as the problem detail member while gin runs in debug mode
+	ProblemDebugDetail bool
 )
 
 //----------------------------------------------------------------------------------------------------------------------
@@ -70,6 +76,11 @@ func (c *Context) UriReq() interface{} {
 	return c.Context.MustGet(string(bind.KeyUri))
 }
 
+// HashIDParam returns the number decoded by bind.HashIDParam for the named param
+func (c *Context) HashIDParam(name string) int64 {
+	return c.Context.MustGet(string(bind.KeyHashIDParam)).(map[string]int64)[name]
+}
+
 func (c *Context) response(handler response.Handler, f func()) {
 	if BeforeResponseCallback != nil {
 		stop := BeforeResponseCallback(c.Context, handler)
@@ -90,52 +101,73 @@ func Wrap(handler func(c *Context) response.Handler) gin.HandlerFunc {
 		c := &Context{Context: ctx}
 		h := handler(c)
 		c.response(h, func() {
-			r := h()
-			for key, val := range r.Header {
-				c.Context.Header(key, val)
-			}
-			normal := gin.H{
-				"code": r.Code,
-				"msg":  r.Msg,
-				"data": r.Data,
-			}
-			switch r.Type {
-			case response.ContentTypeJSON:
-				c.Context.JSON(r.StatusCode, normal)
-			case response.ContentTypeIndentedJSON:
-				c.Context.IndentedJSON(r.StatusCode, normal)
-			case response.ContentTypeSecureJSON:
-				c.Context.SecureJSON(r.StatusCode, normal)
-			case response.ContentTypeJsonpJSON:
-				c.Context.JSONP(r.StatusCode, normal)
-			case response.ContentTypeAsciiJSON:
-				c.Context.AsciiJSON(r.StatusCode, normal)
-			case response.ContentTypePureJSON:
-				c.Context.PureJSON(r.StatusCode, normal)
-			case response.ContentTypeProtoBuf:
-				c.Context.ProtoBuf(r.StatusCode, normal)
-			case response.ContentTypeTOML:
-				c.Context.TOML(r.StatusCode, normal)
-			case response.ContentTypeXML:
-				c.Context.XML(r.StatusCode, normal)
-			case response.ContentTypeYAML:
-				c.Context.YAML(r.StatusCode, normal)
-			case response.ContentTypeMsgPack:
-				c.Context.Render(r.StatusCode, render.MsgPack{Data: normal})
-			case response.ContentTypeRedirect:
-				switch r.Data.(type) {
-				case string:
-					c.Context.Redirect(r.StatusCode, r.Data.(string))
-				}
-			case response.ContentTypeString:
-				switch r.Data.(type) {
-				case string:
-					c.Context.String(r.StatusCode, r.Data.(string))
-				}
-			case response.ContentTypeHTML:
-				c.Context.HTML(r.StatusCode, r.HTMLPath, r.Data)
-			}
+			doRender(ctx, h)
 			ctx.Next()
 		})
 	}
 }
+
+// Render renders h the way Wrap does, e.g. for errors passed to bind.ErrorCallback:
+//
+//	bind.ErrorCallback = func(ctx *gin.Context, err error) {
+//		xgin.Render(ctx, response.BadRequest.WithErr(err))
+//	}
+func Render(ctx *gin.Context, h response.Handler) {
+	c := &Context{Context: ctx}
+	c.response(h, func() {
+		doRender(ctx, h)
+	})
+}
+
+func doRender(ctx *gin.Context, h response.Handler) {
+	r := h().Clone()
+	if r.MsgKey != "" {
+		r.Msg = response.Localize(r.MsgKey, response.ParseAcceptLanguage(ctx.GetHeader("Accept-Language"))...)
+	}
+	if ProblemForErrors && r.StatusCode >= 400 && r.Type != response.ContentTypeRedirect {
+		r.Type = response.ContentTypeProblemJSON
+	}
+	for key, val := range r.Header {
+		ctx.Header(key, val)
+	}
+	normal := envelopeOf(ctx).Build(r, ctx)
+	switch r.Type {
+	case response.ContentTypeJSON:
+		ctx.JSON(r.StatusCode, normal)
+	case response.ContentTypeIndentedJSON:
+		ctx.IndentedJSON(r.StatusCode, normal)
+	case response.ContentTypeSecureJSON:
+		ctx.SecureJSON(r.StatusCode, normal)
+	case response.ContentTypeJsonpJSON:
+		ctx.JSONP(r.StatusCode, normal)
+	case response.ContentTypeAsciiJSON:
+		ctx.AsciiJSON(r.StatusCode, normal)
+	case response.ContentTypePureJSON:
+		ctx.PureJSON(r.StatusCode, normal)
+	case response.ContentTypeProtoBuf:
+		ctx.ProtoBuf(r.StatusCode, normal)
+	case response.ContentTypeTOML:
+		ctx.TOML(r.StatusCode, normal)
+	case response.ContentTypeXML:
+		ctx.XML(r.StatusCode, normal)
+	case response.ContentTypeYAML:
+		ctx.YAML(r.StatusCode, normal)
+	case response.ContentTypeMsgPack:
+		ctx.Render(r.StatusCode, render.MsgPack{Data: normal})
+	case response.ContentTypeRedirect:
+		switch r.Data.(type) {
+		case string:
+			ctx.Redirect(r.StatusCode, r.Data.(string))
+		}
+	case response.ContentTypeString:
+		switch r.Data.(type) {
+		case string:
+			ctx.String(r.StatusCode, r.Data.(string))
+		}
+	case response.ContentTypeHTML:
+		ctx.HTML(r.StatusCode, r.HTMLPath, r.Data)
+	case response.ContentTypeProblemJSON:
+		problem := r.Problem(ctx.Request.URL.RequestURI(), ProblemDebugDetail && gin.IsDebugging())
+		ctx.Render(r.StatusCode, problemJSON{Data: problem})
+	}
+}
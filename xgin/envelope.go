@@ -0,0 +1,65 @@
+package xgin
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/lazyboon/boon/response"
+)
+
+const envelopeKey = "_lazyboon.xgin.envelope.key"
+
+// DefaultEnvelope wraps the body rendered by Wrap unless a route group sets its own with UseEnvelope
+var DefaultEnvelope Envelope = StandardEnvelope{}
+
+// Envelope builds the body Wrap renders for r, it is not used for redirect, string, HTML and problem+json responses
+type Envelope interface {
+	Build(r *response.Response, ctx *gin.Context) interface{}
+}
+
+type EnvelopeFunc func(r *response.Response, ctx *gin.Context) interface{}
+
+func (f EnvelopeFunc) Build(r *response.Response, ctx *gin.Context) interface{} {
+	return f(r, ctx)
+}
+
+// StandardEnvelope renders {"code", "msg", "data"} and the field errors as "errors" if any, with DebugErrs the
+// errors of the response are added as "errs" while gin runs in debug mode
+type StandardEnvelope struct {
+	DebugErrs bool
+}
+
+func (e StandardEnvelope) Build(r *response.Response, ctx *gin.Context) interface{} {
+	body := gin.H{
+		"code": r.Code,
+		"msg":  r.Msg,
+		"data": r.Data,
+	}
+	if len(r.FieldErrors) > 0 {
+		body["errors"] = r.FieldErrors
+	}
+	if e.DebugErrs && gin.IsDebugging() && len(r.Errs) > 0 {
+		body["errs"] = response.ErrMessages(r.Errs)
+	}
+	return body
+}
+
+// RawEnvelope renders the data of the response without any envelope
+var RawEnvelope = EnvelopeFunc(func(r *response.Response, ctx *gin.Context) interface{} {
+	return r.Data
+})
+
+// UseEnvelope sets the envelope of the routes after it, e.g. for a route group
+func UseEnvelope(envelope Envelope) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		ctx.Set(envelopeKey, envelope)
+		ctx.Next()
+	}
+}
+
+func envelopeOf(ctx *gin.Context) Envelope {
+	if val, ok := ctx.Get(envelopeKey); ok {
+		if envelope, ok := val.(Envelope); ok {
+			return envelope
+		}
+	}
+	return DefaultEnvelope
+}
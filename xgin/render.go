@@ -0,0 +1,30 @@
+package xgin
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+var problemJSONContentType = []string{"application/problem+json; charset=utf-8"}
+
+// problemJSON renders Data as JSON with the application/problem+json content type
+type problemJSON struct {
+	Data interface{}
+}
+
+func (r problemJSON) Render(w http.ResponseWriter) error {
+	r.WriteContentType(w)
+	bytes, err := json.Marshal(r.Data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(bytes)
+	return err
+}
+
+func (r problemJSON) WriteContentType(w http.ResponseWriter) {
+	header := w.Header()
+	if val := header["Content-Type"]; len(val) == 0 {
+		header["Content-Type"] = problemJSONContentType
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"github.com/go-redis/redis/v9"
 	"github.com/google/uuid"
+	"github.com/lazyboon/boon/retry"
 	"strconv"
 	"time"
 )
@@ -77,41 +78,28 @@ func NewLock(ctx context.Context, client *redis.Client, key string, expiration t
 	}
 
 	// make sure can exit
-	deadline := time.Now().Add(timeout)
-	if _, ok := ctx.Deadline(); !ok {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithDeadline(ctx, deadline)
-		defer cancel()
-	}
-
-	timer := time.NewTimer(timeout)
-	defer timer.Stop()
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
 
 	value := fmt.Sprintf("%s%s", l.token, l.val)
-	var doCount int
-	for {
+	err := retry.DoContext(ctx, func(ctx context.Context, count int) error {
 		ok, err := l.client.SetNX(ctx, key, value, expiration).Result()
 		if err != nil {
-			return nil, err
-		}
-		if ok {
-			return l, nil
-		}
-		doCount++
-		stop, duration := opts.Backoff.Next(doCount)
-		if stop {
-			return nil, ErrAcquireLock
+			// redis errors are not retried
+			return retry.Permanent(err)
 		}
-		if time.Now().Add(duration).After(deadline) {
-			return nil, ErrAcquireLock
-		}
-		timer.Reset(duration)
-		select {
-		case <-ctx.Done():
-			return nil, ErrAcquireLock
-		case <-timer.C:
+		if !ok {
+			return ErrAcquireLock
 		}
+		return nil
+	}, append([]retry.Option{retry.WithBackoff(opts.Backoff)}, opts.RetryOptions...)...)
+	if errors.Is(err, ErrAcquireLock) {
+		return nil, ErrAcquireLock
+	}
+	if err != nil {
+		return nil, err
 	}
+	return l, nil
 }
 
 func (l *Lock) Key() string {
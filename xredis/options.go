@@ -9,6 +9,7 @@ type LockOption struct {
 	Value           *string
 	BlockingTimeout *time.Duration
 	Backoff         retry.IBackoff
+	RetryOptions    []retry.Option
 }
 
 func NewLockOption() *LockOption {
@@ -32,6 +33,12 @@ func (l *LockOption) SetBackoff(v retry.IBackoff) *LockOption {
 	return l
 }
 
+// SetRetryOptions extra retry options used while acquiring, such as retry.WithOnRetry for metrics
+func (l *LockOption) SetRetryOptions(v ...retry.Option) *LockOption {
+	l.RetryOptions = v
+	return l
+}
+
 func mergeLockOptions(options ...*LockOption) *LockOption {
 	ans := NewLockOption()
 	for _, item := range options {
@@ -44,6 +51,9 @@ func mergeLockOptions(options ...*LockOption) *LockOption {
 		if item.Backoff != nil {
 			ans.Backoff = item.Backoff
 		}
+		if item.RetryOptions != nil {
+			ans.RetryOptions = item.RetryOptions
+		}
 	}
 	return ans
 }